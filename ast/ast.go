@@ -488,6 +488,21 @@ func Walk(n Node, walker Walker) error {
 	return err
 }
 
+// Collect returns all nodes of the type T in a AST tree in
+// the depth first search order.
+func Collect[T Node](root Node) []T {
+	var ret []T
+	_ = Walk(root, func(n Node, entering bool) (WalkStatus, error) {
+		if entering {
+			if v, ok := n.(T); ok {
+				ret = append(ret, v)
+			}
+		}
+		return WalkContinue, nil
+	})
+	return ret
+}
+
 func walkHelper(n Node, walker Walker) (WalkStatus, error) {
 	status, err := walker(n, true)
 	if err != nil || status == WalkStop {
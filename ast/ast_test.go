@@ -67,6 +67,30 @@ func TestWalk(t *testing.T) {
 	}
 }
 
+func TestCollect(t *testing.T) {
+	h1 := NewHeading(1)
+	h2 := NewHeading(2)
+	l1 := NewLink()
+	l2 := NewLink()
+	root := node(NewDocument(),
+		node(h1, NewText(), l1),
+		node(NewParagraph(), NewText(), node(NewEmphasis(1), l2)),
+		h2,
+	)
+
+	headings := Collect[*Heading](root)
+	if !reflect.DeepEqual(headings, []*Heading{h1, h2}) {
+		t.Errorf("Collect() expected = %v, got = %v", []*Heading{h1, h2}, headings)
+	}
+	links := Collect[*Link](root)
+	if !reflect.DeepEqual(links, []*Link{l1, l2}) {
+		t.Errorf("Collect() expected = %v, got = %v", []*Link{l1, l2}, links)
+	}
+	if images := Collect[*Image](root); images != nil {
+		t.Errorf("Collect() expected = nil, got = %v", images)
+	}
+}
+
 func node(n Node, children ...Node) Node {
 	for _, c := range children {
 		n.AppendChild(n, c)
@@ -0,0 +1,242 @@
+package extension
+
+import (
+	"bytes"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var abbreviationListKey = parser.NewContextKey()
+
+type abbreviationParser struct {
+}
+
+var defaultAbbreviationParser = &abbreviationParser{}
+
+// NewAbbreviationParser returns a new parser.BlockParser that can parse
+// PHP Markdown Extra abbreviation definitions like
+// '*[HTML]: Hyper Text Markup Language'.
+func NewAbbreviationParser() parser.BlockParser {
+	return defaultAbbreviationParser
+}
+
+func (b *abbreviationParser) Trigger() []byte {
+	return []byte{'*'}
+}
+
+func (b *abbreviationParser) Open(parent gast.Node, reader text.Reader, pc parser.Context) (gast.Node, parser.State) {
+	line, _ := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || pos+1 >= len(line) || line[pos] != '*' || line[pos+1] != '[' {
+		return nil, parser.NoChildren
+	}
+	rest := line[pos+2:]
+	closer := bytes.Index(rest, []byte("]:"))
+	if closer < 1 || bytes.IndexByte(rest[:closer], '[') > -1 || bytes.IndexByte(rest[:closer], ']') > -1 {
+		return nil, parser.NoChildren
+	}
+	name := rest[:closer]
+	description := util.TrimRightSpace(util.TrimLeftSpace(rest[closer+2:]))
+	reader.Advance(len(line) - 1)
+	return ast.NewAbbreviationDefinition(name, description), parser.NoChildren
+}
+
+func (b *abbreviationParser) Continue(node gast.Node, reader text.Reader, pc parser.Context) parser.State {
+	return parser.Close
+}
+
+func (b *abbreviationParser) Close(node gast.Node, reader text.Reader, pc parser.Context) {
+	var list []*ast.AbbreviationDefinition
+	if tmp := pc.Get(abbreviationListKey); tmp != nil {
+		list = tmp.([]*ast.AbbreviationDefinition)
+	}
+	pc.Set(abbreviationListKey, append(list, node.(*ast.AbbreviationDefinition)))
+	node.Parent().RemoveChild(node.Parent(), node)
+}
+
+func (b *abbreviationParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (b *abbreviationParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+type abbreviationASTTransformer struct {
+}
+
+var defaultAbbreviationASTTransformer = &abbreviationASTTransformer{}
+
+// NewAbbreviationASTTransformer returns a new parser.ASTTransformer that
+// wraps texts that match abbreviation definitions with ast.Abbreviation.
+// Definitions may appear after their usages.
+func NewAbbreviationASTTransformer() parser.ASTTransformer {
+	return defaultAbbreviationASTTransformer
+}
+
+func (a *abbreviationASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	tmp := pc.Get(abbreviationListKey)
+	if tmp == nil {
+		return
+	}
+	pc.Set(abbreviationListKey, nil)
+	var definitions []*ast.AbbreviationDefinition
+	for _, def := range tmp.([]*ast.AbbreviationDefinition) {
+		defined := false
+		for _, d := range definitions {
+			if bytes.Equal(d.Name, def.Name) {
+				defined = true
+				break
+			}
+		}
+		// the first definition takes precedence like link reference definitions.
+		if !defined {
+			definitions = append(definitions, def)
+		}
+	}
+	// longer abbreviations are matched first.
+	sort.SliceStable(definitions, func(i, j int) bool {
+		return len(definitions[i].Name) > len(definitions[j].Name)
+	})
+
+	var texts []*gast.Text
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		switch n.Kind() {
+		case gast.KindCodeSpan, gast.KindAutoLink, gast.KindRawHTML, ast.KindAbbreviation:
+			return gast.WalkSkipChildren, nil
+		}
+		if t, ok := n.(*gast.Text); ok && !t.IsRaw() && t.Segment.Padding == 0 {
+			texts = append(texts, t)
+		}
+		return gast.WalkContinue, nil
+	})
+	source := reader.Source()
+	for _, t := range texts {
+		abbreviateText(t, definitions, source)
+	}
+}
+
+// isAbbreviationBoundary returns true if the given rune can be a boundary of
+// abbreviations.
+func isAbbreviationBoundary(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+}
+
+func abbreviateText(t *gast.Text, definitions []*ast.AbbreviationDefinition, source []byte) {
+	parent := t.Parent()
+	segment := t.Segment
+	start := segment.Start
+	for pos := segment.Start; pos < segment.Stop; {
+		var def *ast.AbbreviationDefinition
+		if r, _ := utf8.DecodeLastRune(source[:pos]); pos == 0 || isAbbreviationBoundary(r) {
+			for _, d := range definitions {
+				stop := pos + len(d.Name)
+				if stop > segment.Stop || !bytes.Equal(source[pos:stop], d.Name) {
+					continue
+				}
+				if r, _ := utf8.DecodeRune(source[stop:]); stop == len(source) || isAbbreviationBoundary(r) {
+					def = d
+					break
+				}
+			}
+		}
+		if def == nil {
+			pos++
+			continue
+		}
+		if pos > start {
+			parent.InsertBefore(parent, t, gast.NewTextSegment(text.NewSegment(start, pos)))
+		}
+		abbr := ast.NewAbbreviation(def.Description)
+		abbr.AppendChild(abbr, gast.NewTextSegment(text.NewSegment(pos, pos+len(def.Name))))
+		parent.InsertBefore(parent, t, abbr)
+		pos += len(def.Name)
+		start = pos
+	}
+	// t remains as a text after the last abbreviation because it has line
+	// breaks.
+	t.Segment = segment.WithStart(start)
+}
+
+// AbbreviationHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders Abbreviation nodes.
+type AbbreviationHTMLRenderer struct {
+	html.Config
+}
+
+// NewAbbreviationHTMLRenderer returns a new AbbreviationHTMLRenderer.
+func NewAbbreviationHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &AbbreviationHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *AbbreviationHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindAbbreviation, r.renderAbbreviation)
+}
+
+// AbbreviationAttributeFilter defines attribute names which abbr elements can have.
+var AbbreviationAttributeFilter = html.GlobalAttributeFilter
+
+func (r *AbbreviationHTMLRenderer) renderAbbreviation(w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		n := node.(*ast.Abbreviation)
+		_, _ = w.WriteString(`<abbr title="`)
+		r.Writer.Write(w, n.Description)
+		_ = w.WriteByte('"')
+		if n.Attributes() != nil {
+			html.RenderAttributes(w, n, AbbreviationAttributeFilter)
+		}
+		_ = w.WriteByte('>')
+	} else {
+		_, _ = w.WriteString("</abbr>")
+	}
+	return gast.WalkContinue, nil
+}
+
+type abbreviation struct {
+	options []html.Option
+}
+
+// Abbreviation is an extension that allow you to use PHP Markdown Extra
+// Abbreviations.
+var Abbreviation = &abbreviation{}
+
+// NewAbbreviation returns a new extension with given options.
+func NewAbbreviation(opts ...html.Option) goldmark.Extender {
+	return &abbreviation{
+		options: opts,
+	}
+}
+
+func (e *abbreviation) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(
+			util.Prioritized(NewAbbreviationParser(), 100),
+		),
+		parser.WithASTTransformers(
+			util.Prioritized(NewAbbreviationASTTransformer(), 999),
+		),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewAbbreviationHTMLRenderer(e.options...), 500),
+	))
+}
@@ -0,0 +1,17 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestAbbreviation(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Abbreviation,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/abbreviation.txt", t, testutil.ParseCliCaseArg()...)
+}
@@ -0,0 +1,184 @@
+package extension
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// alertTypes is a list of alert types that are recognized by GitHub.
+var alertTypes = []string{"note", "tip", "important", "warning", "caution"}
+
+var alertListKey = parser.NewContextKey()
+
+type alertMarker struct {
+	blockquote *gast.Blockquote
+	alertType  string
+}
+
+// parseAlertMarker returns a lower-cased alert type if the given line is
+// an alert marker like '[!NOTE]'.
+func parseAlertMarker(line []byte) (string, bool) {
+	line = util.TrimRightSpace(util.TrimLeftSpace(line))
+	if len(line) < 4 || line[0] != '[' || line[1] != '!' || line[len(line)-1] != ']' {
+		return "", false
+	}
+	name := line[2 : len(line)-1]
+	for _, t := range alertTypes {
+		if bytes.EqualFold(name, []byte(t)) {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+type alertParagraphTransformer struct {
+}
+
+var defaultAlertParagraphTransformer = &alertParagraphTransformer{}
+
+// NewAlertParagraphTransformer returns a new ParagraphTransformer that
+// removes alert markers like '[!NOTE]' from first lines of blockquotes.
+func NewAlertParagraphTransformer() parser.ParagraphTransformer {
+	return defaultAlertParagraphTransformer
+}
+
+func (a *alertParagraphTransformer) Transform(node *gast.Paragraph, reader text.Reader, pc parser.Context) {
+	blockquote, ok := node.Parent().(*gast.Blockquote)
+	if !ok || node.PreviousSibling() != nil {
+		return
+	}
+	lines := node.Lines()
+	if lines.Len() == 0 {
+		return
+	}
+	first := lines.At(0)
+	alertType, ok := parseAlertMarker(first.Value(reader.Source()))
+	if !ok {
+		return
+	}
+	var list []alertMarker
+	if tmp := pc.Get(alertListKey); tmp != nil {
+		list = tmp.([]alertMarker)
+	}
+	pc.Set(alertListKey, append(list, alertMarker{blockquote, alertType}))
+	if lines.Len() == 1 {
+		t := gast.NewTextBlock()
+		t.SetBlankPreviousLines(node.HasBlankPreviousLines())
+		node.Parent().ReplaceChild(node.Parent(), node, t)
+		return
+	}
+	lines.SetSliced(1, lines.Len())
+	node.SetLines(lines)
+}
+
+type alertASTTransformer struct {
+}
+
+var defaultAlertASTTransformer = &alertASTTransformer{}
+
+// NewAlertASTTransformer returns a new parser.ASTTransformer that converts
+// blockquotes that start with alert markers into ast.Alert nodes.
+func NewAlertASTTransformer() parser.ASTTransformer {
+	return defaultAlertASTTransformer
+}
+
+func (a *alertASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	tmp := pc.Get(alertListKey)
+	if tmp == nil {
+		return
+	}
+	pc.Set(alertListKey, nil)
+	for _, marker := range tmp.([]alertMarker) {
+		blockquote := marker.blockquote
+		parent := blockquote.Parent()
+		if parent == nil {
+			continue
+		}
+		alert := ast.NewAlert(marker.alertType)
+		for c := blockquote.FirstChild(); c != nil; {
+			next := c.NextSibling()
+			// a text block that was the marker line.
+			if c.Kind() == gast.KindTextBlock && !c.HasChildren() && c.PreviousSibling() == nil {
+				blockquote.RemoveChild(blockquote, c)
+			} else {
+				alert.AppendChild(alert, c)
+			}
+			c = next
+		}
+		parent.ReplaceChild(parent, blockquote, alert)
+	}
+}
+
+// AlertHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders Alert nodes.
+type AlertHTMLRenderer struct {
+	html.Config
+}
+
+// NewAlertHTMLRenderer returns a new AlertHTMLRenderer.
+func NewAlertHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &AlertHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *AlertHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindAlert, r.renderAlert)
+}
+
+func (r *AlertHTMLRenderer) renderAlert(w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	n := node.(*ast.Alert)
+	if entering {
+		_, _ = w.WriteString(`<div class="markdown-alert markdown-alert-`)
+		_, _ = w.WriteString(n.AlertType)
+		_, _ = w.WriteString("\">\n<p class=\"markdown-alert-title\">")
+		_, _ = w.WriteString(strings.ToUpper(n.AlertType[:1]) + n.AlertType[1:])
+		_, _ = w.WriteString("</p>\n")
+	} else {
+		_, _ = w.WriteString("</div>\n")
+	}
+	return gast.WalkContinue, nil
+}
+
+type alert struct {
+	options []html.Option
+}
+
+// Alert is an extension that allow you to use GitHub alerts like
+// '> [!NOTE]'.
+var Alert = &alert{}
+
+// NewAlert returns a new extension with given options.
+func NewAlert(opts ...html.Option) goldmark.Extender {
+	return &alert{
+		options: opts,
+	}
+}
+
+func (e *alert) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithParagraphTransformers(
+			util.Prioritized(NewAlertParagraphTransformer(), 200),
+		),
+		parser.WithASTTransformers(
+			util.Prioritized(NewAlertASTTransformer(), 999),
+		),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewAlertHTMLRenderer(e.options...), 500),
+	))
+}
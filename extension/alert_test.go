@@ -0,0 +1,17 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestAlert(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Alert,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/alert.txt", t, testutil.ParseCliCaseArg()...)
+}
@@ -0,0 +1,248 @@
+package extension
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var ampDisallowedHTMLRegexp = regexp.MustCompile(`(?i)^\s*</?(?:script|style)(?:[\s/>]|$)`)
+
+var ampDisallowedHTMLBlockRegexp = regexp.MustCompile(`(?i)</?(?:script|style)(?:[\s/>]|$)`)
+
+var ampHTMLTagRegexp = regexp.MustCompile(`<[a-zA-Z][a-zA-Z0-9-]*(?:\s+[^\s"'>/=]+(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'=<>` + "`" + `]+))?)*\s*/?>`)
+
+var ampHTMLAttributeRegexp = regexp.MustCompile(`\s+([^\s"'>/=]+)(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'=<>` + "`" + `]+))?`)
+
+var styleAttributeName = []byte("style")
+
+// removeStyleAttributes returns the given raw HTML without style attributes.
+func removeStyleAttributes(value []byte) []byte {
+	return ampHTMLTagRegexp.ReplaceAllFunc(value, func(tag []byte) []byte {
+		return ampHTMLAttributeRegexp.ReplaceAllFunc(tag, func(attr []byte) []byte {
+			name := ampHTMLAttributeRegexp.FindSubmatch(attr)[1]
+			if bytes.EqualFold(name, styleAttributeName) {
+				return nil
+			}
+			return attr
+		})
+	})
+}
+
+type ampASTTransformer struct {
+}
+
+var defaultAMPASTTransformer = &ampASTTransformer{}
+
+// NewAMPASTTransformer returns a new parser.ASTTransformer that removes
+// style attributes of nodes that are not allowed in AMP pages.
+// Style attributes in raw HTML are removed by the AMPHTMLRenderer.
+func NewAMPASTTransformer() parser.ASTTransformer {
+	return defaultAMPASTTransformer
+}
+
+func (a *ampASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		if _, ok := n.Attribute(styleAttributeName); !ok {
+			return gast.WalkContinue, nil
+		}
+		attrs := n.Attributes()
+		n.RemoveAttributes()
+		for _, attr := range attrs {
+			if !bytes.Equal(attr.Name, styleAttributeName) {
+				n.SetAttribute(attr.Name, attr.Value)
+			}
+		}
+		return gast.WalkContinue, nil
+	})
+}
+
+// AMPHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders images as '<amp-img>' and omits raw HTML that is not allowed
+// in AMP pages. HTML blocks that contain script or style elements are
+// omitted entirely, and style attributes in raw HTML are removed.
+// The ImageDecoding and ImagePlaceholder options are applied to images as
+// the html.Renderer does.
+type AMPHTMLRenderer struct {
+	html.Config
+}
+
+// NewAMPHTMLRenderer returns a new AMPHTMLRenderer.
+func NewAMPHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &AMPHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *AMPHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(gast.KindImage, r.renderImage)
+	reg.Register(gast.KindRawHTML, r.renderRawHTML)
+	reg.Register(gast.KindHTMLBlock, r.renderHTMLBlock)
+}
+
+func (r *AMPHTMLRenderer) renderImage(w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if !entering {
+		return gast.WalkContinue, nil
+	}
+	n := node.(*gast.Image)
+	destination, ok := n.Destination, true
+	if r.URLNormalizer != nil {
+		destination, ok = r.URLNormalizer(destination)
+	}
+	if ok && !r.Unsafe && html.IsDangerousURL(destination) {
+		if r.ImagePlaceholder == nil {
+			destination = nil
+		} else {
+			ok = false
+		}
+	}
+	if !ok {
+		if r.ImagePlaceholder != nil {
+			_, _ = w.Write(r.ImagePlaceholder)
+		} else {
+			_, _ = w.Write(util.EscapeHTML(n.Text(source)))
+		}
+		return gast.WalkSkipChildren, nil
+	}
+	_, _ = w.WriteString("<amp-img src=\"")
+	_, _ = w.Write(util.EscapeHTML(util.URLEscape(destination, true)))
+	_, _ = w.WriteString(`" alt="`)
+	_, _ = w.Write(util.EscapeHTML(n.Text(source)))
+	_ = w.WriteByte('"')
+	if n.Title != nil {
+		_, _ = w.WriteString(` title="`)
+		r.Writer.Write(w, n.Title)
+		_ = w.WriteByte('"')
+	}
+	if r.ImageDecoding != nil {
+		if _, ok := n.AttributeString("decoding"); !ok {
+			_, _ = w.WriteString(` decoding="`)
+			_, _ = w.Write(util.EscapeHTML(r.ImageDecoding))
+			_ = w.WriteByte('"')
+		}
+	}
+	if n.Attributes() != nil {
+		html.RenderAttributes(w, n, html.ImageAttributeFilter)
+	}
+	if _, ok := n.AttributeString("layout"); !ok {
+		_, hasWidth := n.AttributeString("width")
+		_, hasHeight := n.AttributeString("height")
+		// AMP requires width and height except for the 'fill' layout.
+		if hasWidth && hasHeight {
+			_, _ = w.WriteString(` layout="responsive"`)
+		} else {
+			_, _ = w.WriteString(` layout="fill"`)
+		}
+	}
+	_, _ = w.WriteString("></amp-img>")
+	return gast.WalkSkipChildren, nil
+}
+
+func isAMPDisallowedHTML(segments *text.Segments, source []byte) bool {
+	if segments.Len() == 0 {
+		return false
+	}
+	segment := segments.At(0)
+	return ampDisallowedHTMLRegexp.Match(segment.Value(source))
+}
+
+// isAMPDisallowedHTMLBlock returns true if any line of the given HTML block
+// contains script or style elements.
+func isAMPDisallowedHTMLBlock(n *gast.HTMLBlock, source []byte) bool {
+	lines := n.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		line := lines.At(i)
+		if ampDisallowedHTMLBlockRegexp.Match(line.Value(source)) {
+			return true
+		}
+	}
+	return n.HasClosure() && ampDisallowedHTMLBlockRegexp.Match(n.ClosureLine.Value(source))
+}
+
+func (r *AMPHTMLRenderer) renderRawHTML(w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if !entering {
+		return gast.WalkSkipChildren, nil
+	}
+	n := node.(*gast.RawHTML)
+	if r.Unsafe && !isAMPDisallowedHTML(n.Segments, source) {
+		var buf bytes.Buffer
+		l := n.Segments.Len()
+		for i := 0; i < l; i++ {
+			segment := n.Segments.At(i)
+			buf.Write(segment.Value(source))
+		}
+		_, _ = w.Write(removeStyleAttributes(buf.Bytes()))
+		return gast.WalkSkipChildren, nil
+	}
+	_, _ = w.WriteString("<!-- raw HTML omitted -->")
+	return gast.WalkSkipChildren, nil
+}
+
+func (r *AMPHTMLRenderer) renderHTMLBlock(w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	n := node.(*gast.HTMLBlock)
+	allowed := r.Unsafe && !isAMPDisallowedHTMLBlock(n, source)
+	if entering {
+		if allowed {
+			// lines are joined because a tag may span multiple lines.
+			var buf bytes.Buffer
+			l := n.Lines().Len()
+			for i := 0; i < l; i++ {
+				line := n.Lines().At(i)
+				buf.Write(line.Value(source))
+			}
+			r.Writer.SecureWrite(w, removeStyleAttributes(buf.Bytes()))
+		} else {
+			_, _ = w.WriteString("<!-- raw HTML omitted -->\n")
+		}
+	} else {
+		if n.HasClosure() {
+			if allowed {
+				closure := n.ClosureLine
+				r.Writer.SecureWrite(w, removeStyleAttributes(closure.Value(source)))
+			} else {
+				_, _ = w.WriteString("<!-- raw HTML omitted -->\n")
+			}
+		}
+	}
+	return gast.WalkContinue, nil
+}
+
+type amp struct {
+	options []html.Option
+}
+
+// AMP is an extension that renders AMP compatible HTML.
+// Images are rendered as '<amp-img>', and inline styles and scripts are
+// omitted.
+var AMP = &amp{}
+
+// NewAMP returns a new extension with given options.
+func NewAMP(opts ...html.Option) goldmark.Extender {
+	return &amp{
+		options: opts,
+	}
+}
+
+func (e *amp) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewAMPASTTransformer(), 999),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewAMPHTMLRenderer(e.options...), 500),
+	))
+}
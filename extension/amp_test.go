@@ -0,0 +1,157 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/testutil"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+type imageSizeTransformer struct {
+}
+
+func (t *imageSizeTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if img, ok := n.(*gast.Image); ok && entering && string(img.Destination) == "/sized.png" {
+			img.SetAttributeString("width", []byte("640"))
+			img.SetAttributeString("height", []byte("480"))
+		}
+		return gast.WalkContinue, nil
+	})
+}
+
+func TestAMP(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithParserOptions(
+			parser.WithAttribute(),
+			parser.WithASTTransformers(
+				util.Prioritized(&imageSizeTransformer{}, 100),
+			),
+		),
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+		),
+		goldmark.WithExtensions(
+			AMP,
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "images are rendered as amp-img",
+			Markdown:    `![sized](/sized.png "title") ![unsized](/unsized.png)`,
+			Expected:    `<p><amp-img src="/sized.png" alt="sized" title="title" width="640" height="480" layout="responsive"></amp-img> <amp-img src="/unsized.png" alt="unsized" layout="fill"></amp-img></p>`,
+		},
+		t,
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "scripts and inline styles are omitted",
+			Markdown: `# Heading {style="color: red" .title}
+
+<script>
+alert(1)
+</script>
+
+<div>ok</div>
+
+a <style>b</style> <span>c</span>`,
+			Expected: `<h1 class="title">Heading</h1>
+<!-- raw HTML omitted -->
+<!-- raw HTML omitted -->
+<div>ok</div>
+<p>a <!-- raw HTML omitted -->b<!-- raw HTML omitted --> <span>c</span></p>`,
+		},
+		t,
+	)
+}
+
+func TestAMPRawHTMLStyles(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+		),
+		goldmark.WithExtensions(
+			AMP,
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "style attributes in raw HTML are omitted",
+			Markdown: `<div class="a"
+  STYLE="color: red">
+ok
+</div>
+
+a <span style='b' data-style="c" styles=d>e</span> style="f"`,
+			Expected: `<div class="a">
+ok
+</div>
+<p>a <span data-style="c" styles=d>e</span> style=&quot;f&quot;</p>`,
+		},
+		t,
+	)
+}
+
+func TestAMPHTMLBlockScripts(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+		),
+		goldmark.WithExtensions(
+			AMP,
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "HTML blocks that contain scripts or styles are omitted",
+			Markdown: `<div>
+<script>alert(1)</script>
+<style>p{}</style>
+</div>
+
+<div>
+ok
+</div>`,
+			Expected: `<!-- raw HTML omitted -->
+<div>
+ok
+</div>`,
+		},
+		t,
+	)
+}
+
+func TestAMPImageOptions(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithImageDecoding("async"),
+			html.WithImagePlaceholder("<span>blocked</span>"),
+		),
+		goldmark.WithExtensions(
+			AMP,
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "decoding attributes and placeholders are rendered",
+			Markdown:    `![a](/a.png) ![b](javascript:alert(1))`,
+			Expected:    `<p><amp-img src="/a.png" alt="a" decoding="async" layout="fill"></amp-img> <span>blocked</span></p>`,
+		},
+		t,
+	)
+}
@@ -0,0 +1,76 @@
+package ast
+
+import (
+	"fmt"
+
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// An AbbreviationDefinition struct represents an abbreviation definition of
+// Markdown(PHPMarkdownExtra) text like '*[HTML]: Hyper Text Markup Language'.
+type AbbreviationDefinition struct {
+	gast.BaseBlock
+
+	// Name is an abbreviated text.
+	Name []byte
+
+	// Description is a full text of the abbreviation.
+	Description []byte
+}
+
+// Dump implements Node.Dump.
+func (n *AbbreviationDefinition) Dump(source []byte, level int) {
+	m := map[string]string{
+		"Name":        fmt.Sprintf("%s", n.Name),
+		"Description": fmt.Sprintf("%s", n.Description),
+	}
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindAbbreviationDefinition is a NodeKind of the AbbreviationDefinition node.
+var KindAbbreviationDefinition = gast.NewNodeKind("AbbreviationDefinition")
+
+// Kind implements Node.Kind.
+func (n *AbbreviationDefinition) Kind() gast.NodeKind {
+	return KindAbbreviationDefinition
+}
+
+// NewAbbreviationDefinition returns a new AbbreviationDefinition node.
+func NewAbbreviationDefinition(name, description []byte) *AbbreviationDefinition {
+	return &AbbreviationDefinition{
+		Name:        name,
+		Description: description,
+	}
+}
+
+// An Abbreviation struct represents an abbreviation of Markdown
+// (PHPMarkdownExtra) text.
+type Abbreviation struct {
+	gast.BaseInline
+
+	// Description is a full text of the abbreviation.
+	Description []byte
+}
+
+// Dump implements Node.Dump.
+func (n *Abbreviation) Dump(source []byte, level int) {
+	m := map[string]string{
+		"Description": fmt.Sprintf("%s", n.Description),
+	}
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindAbbreviation is a NodeKind of the Abbreviation node.
+var KindAbbreviation = gast.NewNodeKind("Abbreviation")
+
+// Kind implements Node.Kind.
+func (n *Abbreviation) Kind() gast.NodeKind {
+	return KindAbbreviation
+}
+
+// NewAbbreviation returns a new Abbreviation node.
+func NewAbbreviation(description []byte) *Abbreviation {
+	return &Abbreviation{
+		Description: description,
+	}
+}
@@ -0,0 +1,36 @@
+package ast
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// An Alert struct represents a GitHub alert like '> [!NOTE]'.
+type Alert struct {
+	gast.BaseBlock
+
+	// AlertType is a lower-cased type of the alert like 'note' and 'warning'.
+	AlertType string
+}
+
+// Dump implements Node.Dump.
+func (n *Alert) Dump(source []byte, level int) {
+	m := map[string]string{
+		"AlertType": n.AlertType,
+	}
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindAlert is a NodeKind of the Alert node.
+var KindAlert = gast.NewNodeKind("Alert")
+
+// Kind implements Node.Kind.
+func (n *Alert) Kind() gast.NodeKind {
+	return KindAlert
+}
+
+// NewAlert returns a new Alert node.
+func NewAlert(alertType string) *Alert {
+	return &Alert{
+		AlertType: alertType,
+	}
+}
@@ -0,0 +1,44 @@
+package ast
+
+import (
+	"fmt"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// A Comment struct represents an inline comment like '%% comment %%'.
+type Comment struct {
+	gast.BaseInline
+
+	// Segment is a position of the comment text in a source.
+	Segment text.Segment
+}
+
+// Text implements Node.Text.
+func (n *Comment) Text(source []byte) []byte {
+	return n.Segment.Value(source)
+}
+
+// Dump implements Node.Dump.
+func (n *Comment) Dump(source []byte, level int) {
+	m := map[string]string{
+		"Comment": fmt.Sprintf("\"%s\"", n.Text(source)),
+	}
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindComment is a NodeKind of the Comment node.
+var KindComment = gast.NewNodeKind("Comment")
+
+// Kind implements Node.Kind.
+func (n *Comment) Kind() gast.NodeKind {
+	return KindComment
+}
+
+// NewComment returns a new Comment node.
+func NewComment(segment text.Segment) *Comment {
+	return &Comment{
+		Segment: segment,
+	}
+}
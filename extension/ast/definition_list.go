@@ -37,6 +37,10 @@ func NewDefinitionList(offset int, para *gast.Paragraph) *DefinitionList {
 // (PHPMarkdownExtra) text.
 type DefinitionTerm struct {
 	gast.BaseBlock
+
+	// GroupIndex is a 1-based index of the term/description group
+	// that this term belongs to.
+	GroupIndex int
 }
 
 // Dump implements Node.Dump.
@@ -62,6 +66,13 @@ func NewDefinitionTerm() *DefinitionTerm {
 type DefinitionDescription struct {
 	gast.BaseBlock
 	IsTight bool
+
+	// GroupIndex is a 1-based index of the term/description group
+	// that this description belongs to.
+	GroupIndex int
+
+	// Index is a 1-based index of this description in its group.
+	Index int
 }
 
 // Dump implements Node.Dump.
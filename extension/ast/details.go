@@ -0,0 +1,53 @@
+package ast
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// A Details struct represents a collapsible section like
+// '::: details Title'.
+// The first child of a Details node is a DetailsSummary node.
+type Details struct {
+	gast.BaseBlock
+}
+
+// Dump implements Node.Dump.
+func (n *Details) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// KindDetails is a NodeKind of the Details node.
+var KindDetails = gast.NewNodeKind("Details")
+
+// Kind implements Node.Kind.
+func (n *Details) Kind() gast.NodeKind {
+	return KindDetails
+}
+
+// NewDetails returns a new Details node.
+func NewDetails() *Details {
+	return &Details{}
+}
+
+// A DetailsSummary struct represents a title of a collapsible section.
+type DetailsSummary struct {
+	gast.BaseBlock
+}
+
+// Dump implements Node.Dump.
+func (n *DetailsSummary) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// KindDetailsSummary is a NodeKind of the DetailsSummary node.
+var KindDetailsSummary = gast.NewNodeKind("DetailsSummary")
+
+// Kind implements Node.Kind.
+func (n *DetailsSummary) Kind() gast.NodeKind {
+	return KindDetailsSummary
+}
+
+// NewDetailsSummary returns a new DetailsSummary node.
+func NewDetailsSummary() *DetailsSummary {
+	return &DetailsSummary{}
+}
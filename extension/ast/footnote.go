@@ -82,6 +82,9 @@ type Footnote struct {
 	gast.BaseBlock
 	Ref   []byte
 	Index int
+
+	// Contexts are texts around references to this footnote.
+	Contexts [][]byte
 }
 
 // Dump implements Node.Dump.
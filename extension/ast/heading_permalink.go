@@ -0,0 +1,44 @@
+package ast
+
+import (
+	"fmt"
+
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// A HeadingPermalink struct represents a permalink to the heading that
+// contains this node.
+type HeadingPermalink struct {
+	gast.BaseInline
+
+	// Symbol is a text of the permalink.
+	Symbol []byte
+
+	// AriaLabel is an accessible label of the permalink.
+	AriaLabel []byte
+}
+
+// Dump implements Node.Dump.
+func (n *HeadingPermalink) Dump(source []byte, level int) {
+	m := map[string]string{
+		"Symbol":    fmt.Sprintf("%s", n.Symbol),
+		"AriaLabel": fmt.Sprintf("%s", n.AriaLabel),
+	}
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindHeadingPermalink is a NodeKind of the HeadingPermalink node.
+var KindHeadingPermalink = gast.NewNodeKind("HeadingPermalink")
+
+// Kind implements Node.Kind.
+func (n *HeadingPermalink) Kind() gast.NodeKind {
+	return KindHeadingPermalink
+}
+
+// NewHeadingPermalink returns a new HeadingPermalink node.
+func NewHeadingPermalink(symbol, ariaLabel []byte) *HeadingPermalink {
+	return &HeadingPermalink{
+		Symbol:    symbol,
+		AriaLabel: ariaLabel,
+	}
+}
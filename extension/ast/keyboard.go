@@ -0,0 +1,30 @@
+package ast
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// A Keyboard struct represents a keyboard input like '++Ctrl++'.
+// Keyboard nodes of key combinations like '++Ctrl+C++' have a Keyboard child
+// for each key.
+type Keyboard struct {
+	gast.BaseInline
+}
+
+// Dump implements Node.Dump.
+func (n *Keyboard) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// KindKeyboard is a NodeKind of the Keyboard node.
+var KindKeyboard = gast.NewNodeKind("Keyboard")
+
+// Kind implements Node.Kind.
+func (n *Keyboard) Kind() gast.NodeKind {
+	return KindKeyboard
+}
+
+// NewKeyboard returns a new Keyboard node.
+func NewKeyboard() *Keyboard {
+	return &Keyboard{}
+}
@@ -0,0 +1,28 @@
+package ast
+
+import (
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// A Mark struct represents a highlighted text like '==text=='.
+type Mark struct {
+	gast.BaseInline
+}
+
+// Dump implements Node.Dump.
+func (n *Mark) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+// KindMark is a NodeKind of the Mark node.
+var KindMark = gast.NewNodeKind("Mark")
+
+// Kind implements Node.Kind.
+func (n *Mark) Kind() gast.NodeKind {
+	return KindMark
+}
+
+// NewMark returns a new Mark node.
+func NewMark() *Mark {
+	return &Mark{}
+}
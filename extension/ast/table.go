@@ -134,6 +134,15 @@ func NewTableHeader(row *TableRow) *TableHeader {
 type TableCell struct {
 	gast.BaseBlock
 	Alignment Alignment
+
+	// IsRowHeader is true if this cell is a header cell of a body row.
+	IsRowHeader bool
+
+	// ColSpan is a number of columns that this cell spans.
+	ColSpan int
+
+	// RowSpan is a number of rows that this cell spans.
+	RowSpan int
 }
 
 // Dump implements Node.Dump.
@@ -153,5 +162,7 @@ func (n *TableCell) Kind() gast.NodeKind {
 func NewTableCell() *TableCell {
 	return &TableCell{
 		Alignment: AlignNone,
+		ColSpan:   1,
+		RowSpan:   1,
 	}
 }
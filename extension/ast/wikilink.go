@@ -0,0 +1,46 @@
+package ast
+
+import (
+	"fmt"
+
+	gast "github.com/yuin/goldmark/ast"
+)
+
+// A Wikilink struct represents a wiki style link like '[[Target|Label]]'.
+// A Wikilink has a text child that is the label, or the target if the label
+// is omitted.
+type Wikilink struct {
+	gast.BaseInline
+
+	// Target is a page name that the link points to.
+	Target []byte
+
+	// Label is a displayed text of the link. Label is nil if the label is
+	// omitted.
+	Label []byte
+}
+
+// Dump implements Node.Dump.
+func (n *Wikilink) Dump(source []byte, level int) {
+	m := map[string]string{
+		"Target": fmt.Sprintf("%s", n.Target),
+		"Label":  fmt.Sprintf("%s", n.Label),
+	}
+	gast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindWikilink is a NodeKind of the Wikilink node.
+var KindWikilink = gast.NewNodeKind("Wikilink")
+
+// Kind implements Node.Kind.
+func (n *Wikilink) Kind() gast.NodeKind {
+	return KindWikilink
+}
+
+// NewWikilink returns a new Wikilink node.
+func NewWikilink(target, label []byte) *Wikilink {
+	return &Wikilink{
+		Target: target,
+		Label:  label,
+	}
+}
@@ -0,0 +1,63 @@
+package extension
+
+import (
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+type blockIDsASTTransformer struct {
+	generator func(n gast.Node, index int) string
+}
+
+// NewBlockIDsASTTransformer returns a parser.ASTTransformer that sets id
+// attributes generated by the given function on paragraphs and list items.
+// index is an index of the node among nodes of the same kind in document
+// order. Nodes are skipped if the function returns an empty string or they
+// already have id attributes.
+func NewBlockIDsASTTransformer(generator func(n gast.Node, index int) string) parser.ASTTransformer {
+	return &blockIDsASTTransformer{generator}
+}
+
+func (a *blockIDsASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	indexes := map[gast.NodeKind]int{}
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		switch n.Kind() {
+		case gast.KindParagraph, gast.KindListItem:
+		default:
+			return gast.WalkContinue, nil
+		}
+		index := indexes[n.Kind()]
+		indexes[n.Kind()]++
+		if _, ok := n.AttributeString("id"); ok {
+			return gast.WalkContinue, nil
+		}
+		if id := a.generator(n, index); len(id) != 0 {
+			n.SetAttributeString("id", []byte(id))
+		}
+		return gast.WalkContinue, nil
+	})
+}
+
+type blockIDs struct {
+	generator func(n gast.Node, index int) string
+}
+
+// WithBlockIDs returns an extension that renders id attributes generated by
+// the given function on paragraphs and list items. The function receives a
+// node and an index of the node among nodes of the same kind, so ids are
+// stable as long as the document is not changed.
+func WithBlockIDs(generator func(n gast.Node, index int) string) goldmark.Extender {
+	return &blockIDs{generator}
+}
+
+func (e *blockIDs) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewBlockIDsASTTransformer(e.generator), 500),
+	))
+}
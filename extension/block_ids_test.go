@@ -0,0 +1,66 @@
+package extension
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestBlockIDs(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			WithBlockIDs(func(n gast.Node, index int) string {
+				switch n.Kind() {
+				case gast.KindParagraph:
+					return fmt.Sprintf("p-%d", index)
+				case gast.KindListItem:
+					return fmt.Sprintf("li-%d", index)
+				}
+				return ""
+			}),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Paragraphs and list items should have ids",
+			Markdown: `# Heading
+
+first
+
+1. tight
+2. list
+
+- a
+- b
+
+  loose
+
+> quoted
+`,
+			Expected: `<h1>Heading</h1>
+<p id="p-0">first</p>
+<ol>
+<li id="li-0">tight</li>
+<li id="li-1">list</li>
+</ol>
+<ul>
+<li id="li-2">
+<p id="p-1">a</p>
+</li>
+<li id="li-3">
+<p id="p-2">b</p>
+<p id="p-3">loose</p>
+</li>
+</ul>
+<blockquote>
+<p id="p-4">quoted</p>
+</blockquote>`,
+		},
+		t,
+	)
+}
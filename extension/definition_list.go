@@ -1,6 +1,9 @@
 package extension
 
 import (
+	"fmt"
+	"strconv"
+
 	"github.com/yuin/goldmark"
 	gast "github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/extension/ast"
@@ -126,11 +129,19 @@ func (b *definitionDescriptionParser) Open(parent gast.Node, reader text.Reader,
 	}
 	para := list.TemporaryParagraph
 	list.TemporaryParagraph = nil
+	group := 1
+	if last := list.LastChild(); last != nil {
+		group = definitionGroupIndex(last)
+		if para != nil {
+			group++
+		}
+	}
 	if para != nil {
 		lines := para.Lines()
 		l := lines.Len()
 		for i := 0; i < l; i++ {
 			term := ast.NewDefinitionTerm()
+			term.GroupIndex = group
 			segment := lines.At(i)
 			term.Lines().Append(segment.TrimRightSpace(reader.Source()))
 			list.AppendChild(list, term)
@@ -140,7 +151,25 @@ func (b *definitionDescriptionParser) Open(parent gast.Node, reader text.Reader,
 	cpos, padding := util.IndentPosition(line[pos+1:], pos+1, list.Offset-pos-1)
 	reader.AdvanceAndSetPadding(cpos+1, padding)
 
-	return ast.NewDefinitionDescription(), parser.HasChildren
+	desc := ast.NewDefinitionDescription()
+	desc.GroupIndex = group
+	desc.Index = 1
+	if prev, ok := list.LastChild().(*ast.DefinitionDescription); ok {
+		desc.Index = prev.Index + 1
+	}
+	return desc, parser.HasChildren
+}
+
+// definitionGroupIndex returns a 1-based index of the term/description group
+// that the given node belongs to.
+func definitionGroupIndex(n gast.Node) int {
+	switch v := n.(type) {
+	case *ast.DefinitionTerm:
+		return v.GroupIndex
+	case *ast.DefinitionDescription:
+		return v.GroupIndex
+	}
+	return 0
 }
 
 func (b *definitionDescriptionParser) Continue(node gast.Node, reader text.Reader, pc parser.Context) parser.State {
@@ -172,16 +201,251 @@ func (b *definitionDescriptionParser) CanAcceptIndentedLine() bool {
 	return false
 }
 
+// DefinitionListConfig struct holds options for the extension.
+type DefinitionListConfig struct {
+	html.Config
+
+	// Striping indicates that alternating classes("odd" and "even") should be
+	// rendered on successive term/description groups.
+	Striping bool
+
+	// AsTable indicates that definition lists should be rendered as
+	// two-column tables instead of '<dl>'.
+	AsTable bool
+
+	// NumberedDefinitions indicates that descriptions should have data-index
+	// attributes when a term has multiple descriptions.
+	NumberedDefinitions bool
+
+	// ARIA indicates that terms and descriptions should have ARIA roles and
+	// terms should refer to their descriptions by aria-describedby.
+	ARIA bool
+}
+
+// DefinitionListOption interface is a functional option interface for the extension.
+type DefinitionListOption interface {
+	renderer.Option
+	// SetDefinitionListOption sets given option to the extension.
+	SetDefinitionListOption(*DefinitionListConfig)
+}
+
+// NewDefinitionListConfig returns a new Config with defaults.
+func NewDefinitionListConfig() DefinitionListConfig {
+	return DefinitionListConfig{
+		Config:              html.NewConfig(),
+		Striping:            false,
+		AsTable:             false,
+		NumberedDefinitions: false,
+		ARIA:                false,
+	}
+}
+
+// SetOption implements renderer.SetOptioner.
+func (c *DefinitionListConfig) SetOption(name renderer.OptionName, value interface{}) {
+	switch name {
+	case optDefinitionListStriping:
+		c.Striping = value.(bool)
+	case optDefinitionListAsTable:
+		c.AsTable = value.(bool)
+	case optNumberedDefinitions:
+		c.NumberedDefinitions = value.(bool)
+	case optDefinitionListARIA:
+		c.ARIA = value.(bool)
+	default:
+		c.Config.SetOption(name, value)
+	}
+}
+
+type withDefinitionListHTMLOptions struct {
+	value []html.Option
+}
+
+func (o *withDefinitionListHTMLOptions) SetConfig(c *renderer.Config) {
+	if o.value != nil {
+		for _, v := range o.value {
+			v.(renderer.Option).SetConfig(c)
+		}
+	}
+}
+
+func (o *withDefinitionListHTMLOptions) SetDefinitionListOption(c *DefinitionListConfig) {
+	if o.value != nil {
+		for _, v := range o.value {
+			v.SetHTMLOption(&c.Config)
+		}
+	}
+}
+
+// WithDefinitionListHTMLOptions is functional option that wraps goldmark HTMLRenderer options.
+func WithDefinitionListHTMLOptions(opts ...html.Option) DefinitionListOption {
+	return &withDefinitionListHTMLOptions{opts}
+}
+
+const optDefinitionListStriping renderer.OptionName = "DefinitionListStriping"
+
+type withDefinitionListStriping struct {
+}
+
+func (o *withDefinitionListStriping) SetConfig(c *renderer.Config) {
+	c.Options[optDefinitionListStriping] = true
+}
+
+func (o *withDefinitionListStriping) SetDefinitionListOption(c *DefinitionListConfig) {
+	c.Striping = true
+}
+
+// WithDefinitionListStriping is a functional option that renders
+// class="odd" and class="even" on successive term/description groups.
+func WithDefinitionListStriping() DefinitionListOption {
+	return &withDefinitionListStriping{}
+}
+
+const optDefinitionListAsTable renderer.OptionName = "DefinitionListAsTable"
+
+type withDefinitionListAsTable struct {
+}
+
+func (o *withDefinitionListAsTable) SetConfig(c *renderer.Config) {
+	c.Options[optDefinitionListAsTable] = true
+}
+
+func (o *withDefinitionListAsTable) SetDefinitionListOption(c *DefinitionListConfig) {
+	c.AsTable = true
+}
+
+// WithDefinitionListAsTable is a functional option that renders definition
+// lists as tables that have a term column and a description column.
+// Terms that have multiple descriptions span multiple rows.
+func WithDefinitionListAsTable() DefinitionListOption {
+	return &withDefinitionListAsTable{}
+}
+
+const optNumberedDefinitions renderer.OptionName = "NumberedDefinitions"
+
+type withNumberedDefinitions struct {
+}
+
+func (o *withNumberedDefinitions) SetConfig(c *renderer.Config) {
+	c.Options[optNumberedDefinitions] = true
+}
+
+func (o *withNumberedDefinitions) SetDefinitionListOption(c *DefinitionListConfig) {
+	c.NumberedDefinitions = true
+}
+
+// WithNumberedDefinitions is a functional option that renders 1-based
+// data-index attributes on descriptions when a term has multiple
+// descriptions.
+func WithNumberedDefinitions() DefinitionListOption {
+	return &withNumberedDefinitions{}
+}
+
+const optDefinitionListARIA renderer.OptionName = "DefinitionListARIA"
+
+type withDefinitionListARIA struct {
+}
+
+func (o *withDefinitionListARIA) SetConfig(c *renderer.Config) {
+	c.Options[optDefinitionListARIA] = true
+}
+
+func (o *withDefinitionListARIA) SetDefinitionListOption(c *DefinitionListConfig) {
+	c.ARIA = true
+}
+
+// WithDefinitionListARIA is a functional option that renders role="term"
+// and role="definition" on terms and descriptions. Terms and descriptions
+// get ids and terms refer to their descriptions by aria-describedby.
+func WithDefinitionListARIA() DefinitionListOption {
+	return &withDefinitionListARIA{}
+}
+
+type definitionListARIAASTTransformer struct {
+}
+
+var defaultDefinitionListARIAASTTransformer = &definitionListARIAASTTransformer{}
+
+// NewDefinitionListARIAASTTransformer returns a new parser.ASTTransformer
+// that sets ARIA roles, ids and aria-describedby attributes to terms and
+// descriptions of definition lists.
+func NewDefinitionListARIAASTTransformer() parser.ASTTransformer {
+	return defaultDefinitionListARIAASTTransformer
+}
+
+var (
+	attrID              = []byte("id")
+	attrRole            = []byte("role")
+	attrAriaDescribedBy = []byte("aria-describedby")
+)
+
+func (a *definitionListARIAASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering || n.Kind() != ast.KindDefinitionList {
+			return gast.WalkContinue, nil
+		}
+		for c := n.FirstChild(); c != nil; {
+			var terms []gast.Node
+			for ; c != nil && c.Kind() == ast.KindDefinitionTerm; c = c.NextSibling() {
+				terms = append(terms, c)
+			}
+			var descriptions []gast.Node
+			for ; c != nil && c.Kind() == ast.KindDefinitionDescription; c = c.NextSibling() {
+				descriptions = append(descriptions, c)
+			}
+			if len(terms) == 0 {
+				// other kinds of nodes may be inserted by other transformers.
+				if len(descriptions) == 0 {
+					c = c.NextSibling()
+				}
+				continue
+			}
+			var ids [][]byte
+			for _, term := range terms {
+				ids = append(ids, ensureID(term, append([]byte("term-"), term.Text(source)...), pc))
+				term.SetAttribute(attrRole, []byte("term"))
+			}
+			var describedBy []byte
+			for i, description := range descriptions {
+				if i != 0 {
+					describedBy = append(describedBy, ' ')
+				}
+				describedBy = append(describedBy, ensureID(description, append(append([]byte{}, ids[0]...), "-description"...), pc)...)
+				description.SetAttribute(attrRole, []byte("definition"))
+			}
+			if describedBy != nil {
+				for _, term := range terms {
+					term.SetAttribute(attrAriaDescribedBy, describedBy)
+				}
+			}
+		}
+		return gast.WalkSkipChildren, nil
+	})
+}
+
+// ensureID returns an id of the given node. If the node does not have an
+// id, ensureID sets an unique id that is generated from the given value.
+func ensureID(n gast.Node, value []byte, pc parser.Context) []byte {
+	if v, ok := n.Attribute(attrID); ok {
+		if id, ok := v.([]byte); ok {
+			return id
+		}
+	}
+	id := pc.IDs().Generate(value, n.Kind())
+	n.SetAttribute(attrID, id)
+	return id
+}
+
 // DefinitionListHTMLRenderer is a renderer.NodeRenderer implementation that
 // renders DefinitionList nodes.
 type DefinitionListHTMLRenderer struct {
-	html.Config
+	DefinitionListConfig
 }
 
 // NewDefinitionListHTMLRenderer returns a new DefinitionListHTMLRenderer.
 func NewDefinitionListHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
 	r := &DefinitionListHTMLRenderer{
-		Config: html.NewConfig(),
+		DefinitionListConfig: NewDefinitionListConfig(),
 	}
 	for _, opt := range opts {
 		opt.SetHTMLOption(&r.Config)
@@ -189,6 +453,18 @@ func NewDefinitionListHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
 	return r
 }
 
+// NewDefinitionListHTMLRendererWithOptions returns a new DefinitionListHTMLRenderer
+// with given DefinitionListOptions.
+func NewDefinitionListHTMLRendererWithOptions(opts ...DefinitionListOption) renderer.NodeRenderer {
+	r := &DefinitionListHTMLRenderer{
+		DefinitionListConfig: NewDefinitionListConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetDefinitionListOption(&r.DefinitionListConfig)
+	}
+	return r
+}
+
 // RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
 func (r *DefinitionListHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
 	reg.Register(ast.KindDefinitionList, r.renderDefinitionList)
@@ -200,6 +476,9 @@ func (r *DefinitionListHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFunc
 var DefinitionListAttributeFilter = html.GlobalAttributeFilter
 
 func (r *DefinitionListHTMLRenderer) renderDefinitionList(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if r.AsTable {
+		return r.renderDefinitionListAsTable(w, source, n, entering)
+	}
 	if entering {
 		if n.Attributes() != nil {
 			_, _ = w.WriteString("<dl")
@@ -214,18 +493,57 @@ func (r *DefinitionListHTMLRenderer) renderDefinitionList(w util.BufWriter, sour
 	return gast.WalkContinue, nil
 }
 
+// renderAttributes renders attributes of the given term or description.
+// If striping is enabled, a class for the group is appended to
+// the class attribute without modifying the node.
+func (r *DefinitionListHTMLRenderer) renderAttributes(w util.BufWriter, n gast.Node, filter util.BytesFilter) {
+	if r.Striping {
+		if group := definitionGroupIndex(n); group > 0 {
+			class := "odd"
+			if group%2 == 0 {
+				class = "even"
+			}
+			html.RenderAttributesWithClass(w, n, filter, class)
+			return
+		}
+	}
+	if n.Attributes() != nil {
+		html.RenderAttributes(w, n, filter)
+	}
+}
+
+var attrDataIndex = []byte("data-index")
+
+// renderDefinitionIndex renders a data-index attribute of the given
+// description if its group has multiple descriptions.
+func renderDefinitionIndex(w util.BufWriter, n *ast.DefinitionDescription) {
+	if _, ok := n.Attribute(attrDataIndex); ok {
+		return
+	}
+	if n.Index < 1 {
+		return
+	}
+	if next := n.NextSibling(); n.Index == 1 && (next == nil || next.Kind() != ast.KindDefinitionDescription) {
+		return
+	}
+	_, _ = w.WriteString(` data-index="`)
+	_, _ = w.WriteString(strconv.Itoa(n.Index))
+	_ = w.WriteByte('"')
+}
+
 // DefinitionTermAttributeFilter defines attribute names which dd elements can have.
-var DefinitionTermAttributeFilter = html.GlobalAttributeFilter
+var DefinitionTermAttributeFilter = html.GlobalAttributeFilter.Extend(
+	[]byte("aria-describedby"),
+)
 
 func (r *DefinitionListHTMLRenderer) renderDefinitionTerm(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if r.AsTable {
+		return r.renderDefinitionTermAsTable(w, source, n, entering)
+	}
 	if entering {
-		if n.Attributes() != nil {
-			_, _ = w.WriteString("<dt")
-			html.RenderAttributes(w, n, DefinitionTermAttributeFilter)
-			_ = w.WriteByte('>')
-		} else {
-			_, _ = w.WriteString("<dt>")
-		}
+		_, _ = w.WriteString("<dt")
+		r.renderAttributes(w, n, DefinitionTermAttributeFilter)
+		_ = w.WriteByte('>')
 	} else {
 		_, _ = w.WriteString("</dt>\n")
 	}
@@ -236,11 +554,15 @@ func (r *DefinitionListHTMLRenderer) renderDefinitionTerm(w util.BufWriter, sour
 var DefinitionDescriptionAttributeFilter = html.GlobalAttributeFilter
 
 func (r *DefinitionListHTMLRenderer) renderDefinitionDescription(w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if r.AsTable {
+		return r.renderDefinitionDescriptionAsTable(w, source, node, entering)
+	}
 	if entering {
 		n := node.(*ast.DefinitionDescription)
 		_, _ = w.WriteString("<dd")
-		if n.Attributes() != nil {
-			html.RenderAttributes(w, n, DefinitionDescriptionAttributeFilter)
+		r.renderAttributes(w, n, DefinitionDescriptionAttributeFilter)
+		if r.NumberedDefinitions {
+			renderDefinitionIndex(w, n)
 		}
 		if n.IsTight {
 			_, _ = w.WriteString(">")
@@ -253,11 +575,100 @@ func (r *DefinitionListHTMLRenderer) renderDefinitionDescription(w util.BufWrite
 	return gast.WalkContinue, nil
 }
 
+func (r *DefinitionListHTMLRenderer) renderDefinitionListAsTable(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("<table")
+		if n.Attributes() != nil {
+			html.RenderAttributes(w, n, DefinitionListAttributeFilter)
+		}
+		_, _ = w.WriteString(">\n<tbody>\n")
+	} else {
+		_, _ = w.WriteString("</tbody>\n</table>\n")
+	}
+	return gast.WalkContinue, nil
+}
+
+// definitionDescriptionCount returns a number of descriptions that follow
+// the term group that the given term belongs to.
+func definitionDescriptionCount(term gast.Node) int {
+	c := term.NextSibling()
+	for ; c != nil && c.Kind() == ast.KindDefinitionTerm; c = c.NextSibling() {
+	}
+	count := 0
+	for ; c != nil && c.Kind() == ast.KindDefinitionDescription; c = c.NextSibling() {
+		count++
+	}
+	return count
+}
+
+func (r *DefinitionListHTMLRenderer) renderDefinitionTermAsTable(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	prev := n.PreviousSibling()
+	next := n.NextSibling()
+	if entering {
+		if prev != nil && prev.Kind() == ast.KindDefinitionTerm {
+			// terms in the same group share a cell.
+			if r.XHTML {
+				_, _ = w.WriteString("<br />\n")
+			} else {
+				_, _ = w.WriteString("<br>\n")
+			}
+			return gast.WalkContinue, nil
+		}
+		_, _ = w.WriteString("<tr>\n<th")
+		if count := definitionDescriptionCount(n); count > 1 {
+			_, _ = fmt.Fprintf(w, ` rowspan="%d"`, count)
+		}
+		r.renderAttributes(w, n, DefinitionTermAttributeFilter)
+		_ = w.WriteByte('>')
+	} else {
+		if next != nil && next.Kind() == ast.KindDefinitionTerm {
+			return gast.WalkContinue, nil
+		}
+		_, _ = w.WriteString("</th>\n")
+		if next == nil || next.Kind() != ast.KindDefinitionDescription {
+			_, _ = w.WriteString("<td></td>\n</tr>\n")
+		}
+	}
+	return gast.WalkContinue, nil
+}
+
+func (r *DefinitionListHTMLRenderer) renderDefinitionDescriptionAsTable(w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		n := node.(*ast.DefinitionDescription)
+		if prev := n.PreviousSibling(); prev == nil || prev.Kind() != ast.KindDefinitionTerm {
+			_, _ = w.WriteString("<tr>\n")
+		}
+		_, _ = w.WriteString("<td")
+		r.renderAttributes(w, n, DefinitionDescriptionAttributeFilter)
+		if r.NumberedDefinitions {
+			renderDefinitionIndex(w, n)
+		}
+		if n.IsTight {
+			_, _ = w.WriteString(">")
+		} else {
+			_, _ = w.WriteString(">\n")
+		}
+	} else {
+		_, _ = w.WriteString("</td>\n</tr>\n")
+	}
+	return gast.WalkContinue, nil
+}
+
 type definitionList struct {
+	options []DefinitionListOption
 }
 
 // DefinitionList is an extension that allow you to use PHP Markdown Extra Definition lists.
-var DefinitionList = &definitionList{}
+var DefinitionList = &definitionList{
+	options: []DefinitionListOption{},
+}
+
+// NewDefinitionList returns a new extension with given options.
+func NewDefinitionList(opts ...DefinitionListOption) goldmark.Extender {
+	return &definitionList{
+		options: opts,
+	}
+}
 
 func (e *definitionList) Extend(m goldmark.Markdown) {
 	m.Parser().AddOptions(parser.WithBlockParsers(
@@ -265,6 +676,15 @@ func (e *definitionList) Extend(m goldmark.Markdown) {
 		util.Prioritized(NewDefinitionDescriptionParser(), 102),
 	))
 	m.Renderer().AddOptions(renderer.WithNodeRenderers(
-		util.Prioritized(NewDefinitionListHTMLRenderer(), 500),
+		util.Prioritized(NewDefinitionListHTMLRendererWithOptions(e.options...), 500),
 	))
+	config := NewDefinitionListConfig()
+	for _, opt := range e.options {
+		opt.SetDefinitionListOption(&config)
+	}
+	if config.ARIA {
+		m.Parser().AddOptions(parser.WithASTTransformers(
+			util.Prioritized(NewDefinitionListARIAASTTransformer(), 999),
+		))
+	}
 }
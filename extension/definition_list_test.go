@@ -1,11 +1,17 @@
 package extension
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
 	"github.com/yuin/goldmark/renderer/html"
 	"github.com/yuin/goldmark/testutil"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
 )
 
 func TestDefinitionList(t *testing.T) {
@@ -19,3 +25,311 @@ func TestDefinitionList(t *testing.T) {
 	)
 	testutil.DoTestCaseFile(markdown, "_test/definition_list.txt", t, testutil.ParseCliCaseArg()...)
 }
+
+func TestDefinitionListWithAttribute(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithParserOptions(
+			parser.WithAttribute(),
+		),
+		goldmark.WithExtensions(
+			DefinitionList,
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Attributes are not parsed in tight descriptions",
+			Markdown: `Term
+:   a
+    {.x}
+:   b {.y}
+`,
+			Expected: `<dl>
+<dt>Term</dt>
+<dd>a
+{.x}</dd>
+<dd>b {.y}</dd>
+</dl>`,
+		},
+		t,
+	)
+}
+
+func TestDefinitionListStriping(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewDefinitionList(
+				WithDefinitionListStriping(),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Term/description groups should have alternating classes",
+			Markdown: `Apple
+:   Pomaceous fruit.
+
+Orange
+Citrus
+:   Citrus fruit.
+:   Orange color.
+
+Banana
+:   Yellow fruit.
+`,
+			Expected: `<dl>
+<dt class="odd">Apple</dt>
+<dd class="odd">Pomaceous fruit.</dd>
+<dt class="even">Orange</dt>
+<dt class="even">Citrus</dt>
+<dd class="even">Citrus fruit.</dd>
+<dd class="even">Orange color.</dd>
+<dt class="odd">Banana</dt>
+<dd class="odd">Yellow fruit.</dd>
+</dl>`,
+		},
+		t,
+	)
+}
+
+func TestDefinitionListStripingDoesNotModifyNodes(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewDefinitionList(
+				WithDefinitionListStriping(),
+			),
+		),
+	)
+	source := []byte("Apple\n:   Pomaceous fruit.\n\nOrange\n:   Citrus fruit.\n")
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	doc.FirstChild().FirstChild().SetAttributeString("class", "fruit")
+	expected := `<dl>
+<dt class="fruit odd">Apple</dt>
+<dd class="odd">Pomaceous fruit.</dd>
+<dt class="even">Orange</dt>
+<dd class="even">Citrus fruit.</dd>
+</dl>
+`
+	for i := 0; i < 3; i++ {
+		var b bytes.Buffer
+		if err := markdown.Renderer().Render(&b, source, doc); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != expected {
+			t.Errorf("%d: expected %q, but got %q", i, expected, b.String())
+		}
+	}
+}
+
+func TestDefinitionListStripingWithRendererOptions(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithParserOptions(
+			parser.WithBlockParsers(
+				util.Prioritized(NewDefinitionListParser(), 101),
+				util.Prioritized(NewDefinitionDescriptionParser(), 102),
+			),
+		),
+		goldmark.WithRendererOptions(
+			renderer.WithNodeRenderers(
+				util.Prioritized(NewDefinitionListHTMLRenderer(html.WithXHTML()), 500),
+			),
+			WithDefinitionListStriping(),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Striping can be enabled by renderer options",
+			Markdown: `Apple
+:   Pomaceous fruit.
+
+Orange
+:   Citrus fruit.
+`,
+			Expected: `<dl>
+<dt class="odd">Apple</dt>
+<dd class="odd">Pomaceous fruit.</dd>
+<dt class="even">Orange</dt>
+<dd class="even">Citrus fruit.</dd>
+</dl>`,
+		},
+		t,
+	)
+}
+
+func TestDefinitionListAsTable(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewDefinitionList(
+				WithDefinitionListAsTable(),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Definition lists should be rendered as tables",
+			Markdown: `Apple
+:   Pomaceous fruit.
+:   A company.
+
+Orange
+Citrus
+:   Citrus fruit.
+`,
+			Expected: `<table>
+<tbody>
+<tr>
+<th rowspan="2">Apple</th>
+<td>Pomaceous fruit.</td>
+</tr>
+<tr>
+<td>A company.</td>
+</tr>
+<tr>
+<th>Orange<br>
+Citrus</th>
+<td>Citrus fruit.</td>
+</tr>
+</tbody>
+</table>`,
+		},
+		t,
+	)
+}
+
+func TestNumberedDefinitions(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewDefinitionList(
+				WithNumberedDefinitions(),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Multiple descriptions should be numbered",
+			Markdown: `Bank
+:   A financial institution.
+:   The land alongside a river.
+:   A row of similar things.
+
+Apple
+:   Pomaceous fruit.
+`,
+			Expected: `<dl>
+<dt>Bank</dt>
+<dd data-index="1">A financial institution.</dd>
+<dd data-index="2">The land alongside a river.</dd>
+<dd data-index="3">A row of similar things.</dd>
+<dt>Apple</dt>
+<dd>Pomaceous fruit.</dd>
+</dl>`,
+		},
+		t,
+	)
+}
+
+func TestNumberedDefinitionsDoesNotModifyNodes(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewDefinitionList(
+				WithNumberedDefinitions(),
+				WithDefinitionListAsTable(),
+			),
+		),
+	)
+	source := []byte("Bank\n:   A financial institution.\n:   The land alongside a river.\n")
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	expected := `<table>
+<tbody>
+<tr>
+<th rowspan="2">Bank</th>
+<td data-index="1">A financial institution.</td>
+</tr>
+<tr>
+<td data-index="2">The land alongside a river.</td>
+</tr>
+</tbody>
+</table>
+`
+	for i := 0; i < 2; i++ {
+		var b bytes.Buffer
+		if err := markdown.Renderer().Render(&b, source, doc); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != expected {
+			t.Errorf("%d: expected %q, but got %q", i, expected, b.String())
+		}
+	}
+	for c := doc.FirstChild().FirstChild(); c != nil; c = c.NextSibling() {
+		if c.Attributes() != nil {
+			t.Errorf("%s must not have attributes", c.Kind())
+		}
+	}
+}
+
+func TestDefinitionListARIA(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewDefinitionList(
+				WithDefinitionListARIA(),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Terms should refer to their descriptions by aria-describedby",
+			Markdown: `Bank
+:   A financial institution.
+:   The land alongside a river.
+
+Apple
+Orange
+:   A fruit.
+
+Apple
+:   A company.
+`,
+			Expected: `<dl>
+<dt id="term-bank" role="term" aria-describedby="term-bank-description term-bank-description-1">Bank</dt>
+<dd id="term-bank-description" role="definition">A financial institution.</dd>
+<dd id="term-bank-description-1" role="definition">The land alongside a river.</dd>
+<dt id="term-apple" role="term" aria-describedby="term-apple-description">Apple</dt>
+<dt id="term-orange" role="term" aria-describedby="term-apple-description">Orange</dt>
+<dd id="term-apple-description" role="definition">A fruit.</dd>
+<dt id="term-apple-1" role="term" aria-describedby="term-apple-1-description">Apple</dt>
+<dd id="term-apple-1-description" role="definition">A company.</dd>
+</dl>`,
+		},
+		t,
+	)
+}
+
+func TestDefinitionListARIAWithOtherNodes(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			DefinitionList,
+		),
+	)
+	source := []byte("Apple\n: Pomaceous fruit\n")
+	reader := text.NewReader(source)
+	pc := parser.NewContext()
+	doc := markdown.Parser().Parse(reader, parser.WithContext(pc))
+	list := doc.FirstChild()
+	list.InsertBefore(list, list.FirstChild(), gast.NewThematicBreak())
+	NewDefinitionListARIAASTTransformer().Transform(doc.(*gast.Document), reader, pc)
+	term := list.FirstChild().NextSibling()
+	if v, ok := term.AttributeString("role"); !ok || string(v.([]byte)) != "term" {
+		t.Errorf("terms after other nodes should have roles, but got %v", term.Attributes())
+	}
+}
@@ -0,0 +1,237 @@
+package extension
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var detailsName = []byte("details")
+
+var detailsFenceKey = parser.NewContextKey()
+
+type detailsFence struct {
+	node   gast.Node
+	length int
+}
+
+type detailsParser struct {
+}
+
+var defaultDetailsParser = &detailsParser{}
+
+// NewDetailsParser returns a new parser.BlockParser that can parse
+// collapsible sections like
+//
+//	::: details Title
+//	contents
+//	:::
+//
+// Sections that are not closed are closed at the end of the parent block.
+func NewDetailsParser() parser.BlockParser {
+	return defaultDetailsParser
+}
+
+func (b *detailsParser) Trigger() []byte {
+	return []byte{':'}
+}
+
+func (b *detailsParser) Open(parent gast.Node, reader text.Reader, pc parser.Context) (gast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || line[pos] != ':' {
+		return nil, parser.NoChildren
+	}
+	i := pos
+	for ; i < len(line) && line[i] == ':'; i++ {
+	}
+	length := i - pos
+	if length < 3 {
+		return nil, parser.NoChildren
+	}
+	i += util.TrimLeftSpaceLength(line[i:])
+	if !bytes.HasPrefix(line[i:], detailsName) {
+		return nil, parser.NoChildren
+	}
+	i += len(detailsName)
+	rest := line[i:]
+	if len(rest) != 0 && !util.IsSpace(rest[0]) {
+		return nil, parser.NoChildren
+	}
+	node := ast.NewDetails()
+	summary := ast.NewDetailsSummary()
+	left := util.TrimLeftSpaceLength(rest)
+	right := util.TrimRightSpaceLength(rest)
+	if left < len(rest)-right {
+		start := segment.Start - segment.Padding + i + left
+		summary.Lines().Append(text.NewSegment(start, segment.Stop-right))
+	}
+	node.AppendChild(node, summary)
+
+	var fences []*detailsFence
+	if tmp := pc.Get(detailsFenceKey); tmp != nil {
+		fences = tmp.([]*detailsFence)
+	}
+	pc.Set(detailsFenceKey, append(fences, &detailsFence{node, length}))
+	reader.Advance(segment.Len() - newlineLength(line))
+	return node, parser.HasChildren
+}
+
+func newlineLength(line []byte) int {
+	if len(line) != 0 && line[len(line)-1] == '\n' {
+		return 1
+	}
+	return 0
+}
+
+func (b *detailsParser) Continue(node gast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, segment := reader.PeekLine()
+	if util.IsBlank(line) {
+		return parser.Continue | parser.HasChildren
+	}
+	w, pos := util.IndentWidth(line, reader.LineOffset())
+	if w > 3 {
+		return parser.Continue | parser.HasChildren
+	}
+	i := pos
+	for ; i < len(line) && line[i] == ':'; i++ {
+	}
+	if i-pos < detailsFenceLength(node, pc) || !util.IsBlank(line[i:]) {
+		return parser.Continue | parser.HasChildren
+	}
+	// closing fences in code blocks and inner sections are not closing
+	// fences of this section.
+	opened := pc.OpenedBlocks()
+	for j := len(opened) - 1; j >= 0 && opened[j].Node != node; j-- {
+		switch opened[j].Node.Kind() {
+		case ast.KindDetails, gast.KindFencedCodeBlock, gast.KindHTMLBlock:
+			return parser.Continue | parser.HasChildren
+		}
+	}
+	reader.Advance(segment.Len() - newlineLength(line))
+	return parser.Close
+}
+
+func detailsFenceLength(node gast.Node, pc parser.Context) int {
+	if tmp := pc.Get(detailsFenceKey); tmp != nil {
+		for _, fence := range tmp.([]*detailsFence) {
+			if fence.node == node {
+				return fence.length
+			}
+		}
+	}
+	return 3
+}
+
+func (b *detailsParser) Close(node gast.Node, reader text.Reader, pc parser.Context) {
+	tmp := pc.Get(detailsFenceKey)
+	if tmp == nil {
+		return
+	}
+	fences := tmp.([]*detailsFence)
+	for i, fence := range fences {
+		if fence.node == node {
+			fences = append(fences[:i], fences[i+1:]...)
+			break
+		}
+	}
+	if len(fences) == 0 {
+		pc.Set(detailsFenceKey, nil)
+	} else {
+		pc.Set(detailsFenceKey, fences)
+	}
+}
+
+func (b *detailsParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (b *detailsParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+// DetailsHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders Details nodes.
+type DetailsHTMLRenderer struct {
+	html.Config
+}
+
+// NewDetailsHTMLRenderer returns a new DetailsHTMLRenderer.
+func NewDetailsHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &DetailsHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *DetailsHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindDetails, r.renderDetails)
+	reg.Register(ast.KindDetailsSummary, r.renderDetailsSummary)
+}
+
+// DetailsAttributeFilter defines attribute names which details elements can have.
+var DetailsAttributeFilter = html.GlobalAttributeFilter.Extend(
+	[]byte("open"),
+)
+
+func (r *DetailsHTMLRenderer) renderDetails(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("<details")
+		if n.Attributes() != nil {
+			html.RenderAttributes(w, n, DetailsAttributeFilter)
+		}
+		_, _ = w.WriteString(">\n")
+	} else {
+		_, _ = w.WriteString("</details>\n")
+	}
+	return gast.WalkContinue, nil
+}
+
+var defaultDetailsSummary = []byte("Details")
+
+func (r *DetailsHTMLRenderer) renderDetailsSummary(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("<summary>")
+		if !n.HasChildren() {
+			_, _ = w.Write(defaultDetailsSummary)
+		}
+	} else {
+		_, _ = w.WriteString("</summary>\n")
+	}
+	return gast.WalkContinue, nil
+}
+
+type details struct {
+	options []html.Option
+}
+
+// Details is an extension that allow you to use collapsible sections like
+// '::: details Title'.
+var Details = &details{}
+
+// NewDetails returns a new extension with given options.
+func NewDetails(opts ...html.Option) goldmark.Extender {
+	return &details{
+		options: opts,
+	}
+}
+
+func (e *details) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithBlockParsers(
+		util.Prioritized(NewDetailsParser(), 150),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewDetailsHTMLRenderer(e.options...), 500),
+	))
+}
@@ -0,0 +1,17 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestDetails(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Details,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/details.txt", t, testutil.ParseCliCaseArg()...)
+}
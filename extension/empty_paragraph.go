@@ -0,0 +1,82 @@
+package extension
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+type emptyParagraphASTTransformer struct {
+}
+
+var defaultEmptyParagraphASTTransformer = &emptyParagraphASTTransformer{}
+
+// NewEmptyParagraphASTTransformer returns a new parser.ASTTransformer that
+// removes paragraphs that contain only whitespace or invisible characters
+// like '&nbsp;' and zero-width spaces.
+func NewEmptyParagraphASTTransformer() parser.ASTTransformer {
+	return defaultEmptyParagraphASTTransformer
+}
+
+func (a *emptyParagraphASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	var paragraphs []gast.Node
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		if n.Kind() == gast.KindParagraph {
+			if isEmptyParagraph(n, source) {
+				paragraphs = append(paragraphs, n)
+			}
+			return gast.WalkSkipChildren, nil
+		}
+		return gast.WalkContinue, nil
+	})
+	for _, p := range paragraphs {
+		p.Parent().RemoveChild(p.Parent(), p)
+	}
+}
+
+func isEmptyParagraph(n gast.Node, source []byte) bool {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		var value []byte
+		switch v := c.(type) {
+		case *gast.Text:
+			value = v.Segment.Value(source)
+		case *gast.String:
+			value = v.Value
+		default:
+			return false
+		}
+		value = util.ResolveNumericReferences(util.ResolveEntityNames(value))
+		for len(value) != 0 {
+			r, size := utf8.DecodeRune(value)
+			if !unicode.IsSpace(r) && !unicode.Is(unicode.Cf, r) {
+				return false
+			}
+			value = value[size:]
+		}
+	}
+	return true
+}
+
+type dropEmptyParagraphs struct {
+}
+
+// DropEmptyParagraphs returns an extension that removes paragraphs whose
+// text content is only whitespace or invisible characters.
+func DropEmptyParagraphs() goldmark.Extender {
+	return &dropEmptyParagraphs{}
+}
+
+func (e *dropEmptyParagraphs) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewEmptyParagraphASTTransformer(), 100),
+	))
+}
@@ -0,0 +1,26 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestDropEmptyParagraphs(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			DropEmptyParagraphs(),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "paragraphs with only invisible characters are removed",
+			Markdown:    "&nbsp;\n\nreal text\n\n&#x200B;&nbsp;\n\n&nbsp;*empty*",
+			Expected:    "<p>real text</p>\n<p>\u00a0<em>empty</em></p>",
+		},
+		t,
+	)
+}
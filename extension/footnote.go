@@ -17,6 +17,7 @@ import (
 
 var footnoteListKey = parser.NewContextKey()
 var footnoteLinkListKey = parser.NewContextKey()
+var footnoteContainerListKey = parser.NewContextKey()
 
 type footnoteBlockParser struct {
 }
@@ -93,6 +94,16 @@ func (b *footnoteBlockParser) Close(node gast.Node, reader text.Reader, pc parse
 		pc.Set(footnoteListKey, list)
 		node.Parent().InsertBefore(node.Parent(), node, list)
 	}
+	if parent := node.Parent(); parent.Kind() != gast.KindDocument {
+		// footnote definitions written in containers like blockquotes and
+		// lists are collected at the document level. Containers that become
+		// empty are removed by the footnoteASTTransformer.
+		var containers []gast.Node
+		if tmp := pc.Get(footnoteContainerListKey); tmp != nil {
+			containers = tmp.([]gast.Node)
+		}
+		pc.Set(footnoteContainerListKey, append(containers, parent))
+	}
 	node.Parent().RemoveChild(node.Parent(), node)
 	list.AppendChild(list, node)
 }
@@ -197,20 +208,27 @@ func NewFootnoteASTTransformer() parser.ASTTransformer {
 func (a *footnoteASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
 	var list *ast.FootnoteList
 	var fnlist []*ast.FootnoteLink
+	var containers []gast.Node
 	if tmp := pc.Get(footnoteListKey); tmp != nil {
 		list = tmp.(*ast.FootnoteList)
 	}
 	if tmp := pc.Get(footnoteLinkListKey); tmp != nil {
 		fnlist = tmp.([]*ast.FootnoteLink)
 	}
+	if tmp := pc.Get(footnoteContainerListKey); tmp != nil {
+		containers = tmp.([]gast.Node)
+	}
 
 	pc.Set(footnoteListKey, nil)
 	pc.Set(footnoteLinkListKey, nil)
+	pc.Set(footnoteContainerListKey, nil)
 
 	if list == nil {
 		return
 	}
+	defer removeEmptyFootnoteContainers(containers)
 
+	fnlist = renumberFootnotes(list, fnlist)
 	counter := map[int]int{}
 	if fnlist != nil {
 		for _, fnlink := range fnlist {
@@ -269,6 +287,177 @@ func (a *footnoteASTTransformer) Transform(node *gast.Document, reader text.Read
 	node.AppendChild(node, list)
 }
 
+// renumberFootnotes numbers footnotes in the order they are first referenced
+// in the main text. Footnotes referenced only from other footnote definitions
+// are numbered after them in the order they are found in the definitions.
+// Footnotes that can not be reached from the main text, including cycles of
+// definitions that only reference each other, get -1 as their indices.
+// renumberFootnotes returns links that are reachable from the main text.
+func renumberFootnotes(list *ast.FootnoteList, fnlist []*ast.FootnoteLink) []*ast.FootnoteLink {
+	footnotes := map[int]*ast.Footnote{}
+	for c := list.FirstChild(); c != nil; c = c.NextSibling() {
+		if fn := c.(*ast.Footnote); fn.Index >= 0 {
+			footnotes[fn.Index] = fn
+		}
+	}
+	var links []*ast.FootnoteLink
+	nested := map[*ast.Footnote][]*ast.FootnoteLink{}
+	for _, fnlink := range fnlist {
+		if owner := footnoteOf(fnlink); owner != nil {
+			nested[owner] = append(nested[owner], fnlink)
+		} else {
+			links = append(links, fnlink)
+		}
+	}
+	indices := map[*ast.Footnote]int{}
+	var order []*ast.Footnote
+	visit := func(fnlinks []*ast.FootnoteLink) {
+		for _, fnlink := range fnlinks {
+			fn, ok := footnotes[fnlink.Index]
+			if !ok {
+				continue
+			}
+			if _, ok := indices[fn]; !ok {
+				order = append(order, fn)
+				indices[fn] = len(order)
+			}
+		}
+	}
+	visit(links)
+	// footnotes that are already numbered are never visited twice, so
+	// cycles of references are broken here.
+	for i := 0; i < len(order); i++ {
+		visit(nested[order[i]])
+		links = append(links, nested[order[i]]...)
+	}
+	for _, fnlink := range links {
+		fnlink.Index = indices[footnotes[fnlink.Index]]
+	}
+	for c := list.FirstChild(); c != nil; c = c.NextSibling() {
+		fn := c.(*ast.Footnote)
+		if index, ok := indices[fn]; ok {
+			fn.Index = index
+		} else {
+			fn.Index = -1
+		}
+	}
+	list.Count = len(order)
+	return links
+}
+
+func footnoteOf(n gast.Node) *ast.Footnote {
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		if fn, ok := p.(*ast.Footnote); ok {
+			return fn
+		}
+	}
+	return nil
+}
+
+func removeEmptyFootnoteContainers(containers []gast.Node) {
+	for _, container := range containers {
+		for c := container; c != nil && c.Parent() != nil && c.Kind() != gast.KindDocument; {
+			if c.HasChildren() {
+				break
+			}
+			parent := c.Parent()
+			parent.RemoveChild(parent, c)
+			c = parent
+		}
+	}
+}
+
+type footnoteContextASTTransformer struct {
+	length int
+}
+
+// NewFootnoteContextASTTransformer returns a new parser.ASTTransformer that
+// captures given number of characters around each footnote reference as
+// contexts of footnotes.
+func NewFootnoteContextASTTransformer(length int) parser.ASTTransformer {
+	return &footnoteContextASTTransformer{
+		length: length,
+	}
+}
+
+func (a *footnoteContextASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	var links []*ast.FootnoteLink
+	footnotes := map[int]*ast.Footnote{}
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		switch v := n.(type) {
+		case *ast.FootnoteLink:
+			links = append(links, v)
+		case *ast.Footnote:
+			footnotes[v.Index] = v
+		}
+		return gast.WalkContinue, nil
+	})
+	for _, link := range links {
+		if fn, ok := footnotes[link.Index]; ok {
+			fn.Contexts = append(fn.Contexts, footnoteContext(link, reader.Source(), a.length))
+		}
+	}
+}
+
+// footnoteContext returns a text around the given footnote link in the
+// block that contains the link.
+func footnoteContext(link *ast.FootnoteLink, source []byte, length int) []byte {
+	block := link.Parent()
+	for block != nil && block.Type() != gast.TypeBlock {
+		block = block.Parent()
+	}
+	if block == nil {
+		return nil
+	}
+	var before, after []byte
+	found := false
+	_ = gast.Walk(block, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		if n == link {
+			found = true
+			return gast.WalkSkipChildren, nil
+		}
+		var value []byte
+		switch v := n.(type) {
+		case *gast.Text:
+			value = v.Segment.Value(source)
+			if v.SoftLineBreak() || v.HardLineBreak() {
+				value = append(value[:len(value):len(value)], ' ')
+			}
+		case *gast.String:
+			value = v.Value
+		case *ast.FootnoteLink, *ast.FootnoteBacklink:
+			return gast.WalkSkipChildren, nil
+		}
+		if found {
+			after = append(after, value...)
+		} else {
+			before = append(before, value...)
+		}
+		return gast.WalkContinue, nil
+	})
+	beforeRunes := []rune(string(bytes.TrimLeft(before, " ")))
+	afterRunes := []rune(string(bytes.TrimRight(after, " ")))
+	var buf bytes.Buffer
+	if len(beforeRunes) > length {
+		buf.WriteString("…")
+		beforeRunes = beforeRunes[len(beforeRunes)-length:]
+	}
+	buf.WriteString(string(beforeRunes))
+	if len(afterRunes) > length {
+		buf.WriteString(string(afterRunes[:length]))
+		buf.WriteString("…")
+	} else {
+		buf.WriteString(string(afterRunes))
+	}
+	return buf.Bytes()
+}
+
 // FootnoteConfig holds configuration values for the footnote extension.
 //
 // Link* and Backlink* configurations have some variables:
@@ -285,6 +474,10 @@ type FootnoteConfig struct {
 	// IDPrefix is a function that determines the id attribute for given Node.
 	IDPrefixFunction func(gast.Node) []byte
 
+	// RefIDPrefix is a prefix for the id attributes of footnote references.
+	// IDPrefix is used if RefIDPrefix is nil.
+	RefIDPrefix []byte
+
 	// LinkTitle is an optional title attribute for footnote links.
 	LinkTitle []byte
 
@@ -299,8 +492,29 @@ type FootnoteConfig struct {
 
 	// BacklinkHTML is an HTML content for footnote backlinks.
 	BacklinkHTML []byte
+
+	// RefStyle indicates how footnote links are rendered.
+	RefStyle FootnoteRefStyle
+
+	// ContextLength is a number of characters captured before and after
+	// each footnote reference. Captured contexts are rendered in footnotes.
+	// 0 means no contexts are captured.
+	ContextLength int
 }
 
+// FootnoteRefStyle indicates how footnote links are rendered in HTML format.
+type FootnoteRefStyle int
+
+const (
+	// FootnoteRefStyleSuperscript renders footnote links as superscripts
+	// like '<sup>1</sup>'.
+	FootnoteRefStyleSuperscript FootnoteRefStyle = iota
+
+	// FootnoteRefStyleParenthetical renders footnote links in parentheses
+	// like '(1)'.
+	FootnoteRefStyleParenthetical
+)
+
 // FootnoteOption interface is a functional option interface for the extension.
 type FootnoteOption interface {
 	renderer.Option
@@ -317,6 +531,7 @@ func NewFootnoteConfig() FootnoteConfig {
 		LinkClass:     []byte("footnote-ref"),
 		BacklinkClass: []byte("footnote-backref"),
 		BacklinkHTML:  []byte("&#x21a9;&#xfe0e;"),
+		RefStyle:      FootnoteRefStyleSuperscript,
 	}
 }
 
@@ -327,6 +542,8 @@ func (c *FootnoteConfig) SetOption(name renderer.OptionName, value interface{})
 		c.IDPrefixFunction = value.(func(gast.Node) []byte)
 	case optFootnoteIDPrefix:
 		c.IDPrefix = value.([]byte)
+	case optFootnoteRefIDPrefix:
+		c.RefIDPrefix = value.([]byte)
 	case optFootnoteLinkTitle:
 		c.LinkTitle = value.([]byte)
 	case optFootnoteBacklinkTitle:
@@ -337,6 +554,10 @@ func (c *FootnoteConfig) SetOption(name renderer.OptionName, value interface{})
 		c.BacklinkClass = value.([]byte)
 	case optFootnoteBacklinkHTML:
 		c.BacklinkHTML = value.([]byte)
+	case optFootnoteRefStyle:
+		c.RefStyle = value.(FootnoteRefStyle)
+	case optFootnoteContext:
+		c.ContextLength = value.(int)
 	default:
 		c.Config.SetOption(name, value)
 	}
@@ -405,6 +626,25 @@ func WithFootnoteIDPrefixFunction(a func(gast.Node) []byte) FootnoteOption {
 	return &withFootnoteIDPrefixFunction{a}
 }
 
+const optFootnoteRefIDPrefix renderer.OptionName = "FootnoteRefIDPrefix"
+
+type withFootnoteRefIDPrefix struct {
+	value []byte
+}
+
+func (o *withFootnoteRefIDPrefix) SetConfig(c *renderer.Config) {
+	c.Options[optFootnoteRefIDPrefix] = o.value
+}
+
+func (o *withFootnoteRefIDPrefix) SetFootnoteOption(c *FootnoteConfig) {
+	c.RefIDPrefix = o.value
+}
+
+// WithFootnoteRefIDPrefix is a functional option that is a prefix for the id attributes of footnote references.
+func WithFootnoteRefIDPrefix(a []byte) FootnoteOption {
+	return &withFootnoteRefIDPrefix{a}
+}
+
 const optFootnoteLinkTitle renderer.OptionName = "FootnoteLinkTitle"
 
 type withFootnoteLinkTitle struct {
@@ -500,6 +740,47 @@ func WithFootnoteBacklinkHTML(a []byte) FootnoteOption {
 	return &withFootnoteBacklinkHTML{a}
 }
 
+const optFootnoteRefStyle renderer.OptionName = "FootnoteRefStyle"
+
+type withFootnoteRefStyle struct {
+	value FootnoteRefStyle
+}
+
+func (o *withFootnoteRefStyle) SetConfig(c *renderer.Config) {
+	c.Options[optFootnoteRefStyle] = o.value
+}
+
+func (o *withFootnoteRefStyle) SetFootnoteOption(c *FootnoteConfig) {
+	c.RefStyle = o.value
+}
+
+// WithFootnoteRefStyle is a functional option that indicates how footnote
+// links are rendered.
+func WithFootnoteRefStyle(a FootnoteRefStyle) FootnoteOption {
+	return &withFootnoteRefStyle{a}
+}
+
+const optFootnoteContext renderer.OptionName = "FootnoteContext"
+
+type withFootnoteContext struct {
+	value int
+}
+
+func (o *withFootnoteContext) SetConfig(c *renderer.Config) {
+	c.Options[optFootnoteContext] = o.value
+}
+
+func (o *withFootnoteContext) SetFootnoteOption(c *FootnoteConfig) {
+	c.ContextLength = o.value
+}
+
+// WithFootnoteContext is a functional option that captures n characters
+// before and after each footnote reference and renders them in footnotes
+// as '<p class="footnote-context">'.
+func WithFootnoteContext(n int) FootnoteOption {
+	return &withFootnoteContext{n}
+}
+
 // FootnoteHTMLRenderer is a renderer.NodeRenderer implementation that
 // renders FootnoteLink nodes.
 type FootnoteHTMLRenderer struct {
@@ -529,15 +810,25 @@ func (r *FootnoteHTMLRenderer) renderFootnoteLink(w util.BufWriter, source []byt
 	if entering {
 		n := node.(*ast.FootnoteLink)
 		is := strconv.Itoa(n.Index)
-		_, _ = w.WriteString(`<sup id="`)
-		_, _ = w.Write(r.idPrefix(node))
+		tag := "sup"
+		if r.FootnoteConfig.RefStyle == FootnoteRefStyleParenthetical {
+			tag = "span"
+		}
+		_ = w.WriteByte('<')
+		_, _ = w.WriteString(tag)
+		_, _ = w.WriteString(` id="`)
+		_, _ = w.Write(r.refIDPrefix(node))
 		_, _ = w.WriteString(`fnref`)
 		if n.RefIndex > 0 {
 			_, _ = w.WriteString(fmt.Sprintf("%v", n.RefIndex))
 		}
 		_ = w.WriteByte(':')
 		_, _ = w.WriteString(is)
-		_, _ = w.WriteString(`"><a href="#`)
+		_, _ = w.WriteString(`">`)
+		if tag == "span" {
+			_ = w.WriteByte('(')
+		}
+		_, _ = w.WriteString(`<a href="#`)
 		_, _ = w.Write(r.idPrefix(node))
 		_, _ = w.WriteString(`fn:`)
 		_, _ = w.WriteString(is)
@@ -551,7 +842,13 @@ func (r *FootnoteHTMLRenderer) renderFootnoteLink(w util.BufWriter, source []byt
 		_, _ = w.WriteString(`" role="doc-noteref">`)
 
 		_, _ = w.WriteString(is)
-		_, _ = w.WriteString(`</a></sup>`)
+		_, _ = w.WriteString(`</a>`)
+		if tag == "span" {
+			_ = w.WriteByte(')')
+		}
+		_, _ = w.WriteString(`</`)
+		_, _ = w.WriteString(tag)
+		_ = w.WriteByte('>')
 	}
 	return gast.WalkContinue, nil
 }
@@ -561,7 +858,7 @@ func (r *FootnoteHTMLRenderer) renderFootnoteBacklink(w util.BufWriter, source [
 		n := node.(*ast.FootnoteBacklink)
 		is := strconv.Itoa(n.Index)
 		_, _ = w.WriteString(`&#160;<a href="#`)
-		_, _ = w.Write(r.idPrefix(node))
+		_, _ = w.Write(r.refIDPrefix(node))
 		_, _ = w.WriteString(`fnref`)
 		if n.RefIndex > 0 {
 			_, _ = w.WriteString(fmt.Sprintf("%v", n.RefIndex))
@@ -594,6 +891,11 @@ func (r *FootnoteHTMLRenderer) renderFootnote(w util.BufWriter, source []byte, n
 			html.RenderAttributes(w, node, html.ListItemAttributeFilter)
 		}
 		_, _ = w.WriteString(">\n")
+		for _, context := range n.Contexts {
+			_, _ = w.WriteString(`<p class="footnote-context">`)
+			_, _ = w.Write(util.EscapeHTML(context))
+			_, _ = w.WriteString("</p>\n")
+		}
 	} else {
 		_, _ = w.WriteString("</li>\n")
 	}
@@ -630,6 +932,13 @@ func (r *FootnoteHTMLRenderer) idPrefix(node gast.Node) []byte {
 	return []byte("")
 }
 
+func (r *FootnoteHTMLRenderer) refIDPrefix(node gast.Node) []byte {
+	if r.FootnoteConfig.RefIDPrefix != nil {
+		return r.FootnoteConfig.RefIDPrefix
+	}
+	return r.idPrefix(node)
+}
+
 func applyFootnoteTemplate(b []byte, index, refCount int) []byte {
 	fast := true
 	for i, c := range b {
@@ -681,6 +990,17 @@ func (e *footnote) Extend(m goldmark.Markdown) {
 			util.Prioritized(NewFootnoteASTTransformer(), 999),
 		),
 	)
+	config := NewFootnoteConfig()
+	for _, opt := range e.options {
+		opt.SetFootnoteOption(&config)
+	}
+	if config.ContextLength > 0 {
+		m.Parser().AddOptions(
+			parser.WithASTTransformers(
+				util.Prioritized(NewFootnoteContextASTTransformer(config.ContextLength), 1000),
+			),
+		)
+	}
 	m.Renderer().AddOptions(renderer.WithNodeRenderers(
 		util.Prioritized(NewFootnoteHTMLRenderer(e.options...), 500),
 	))
@@ -139,3 +139,186 @@ Another one.[^2]
 		t,
 	)
 }
+
+func TestFootnoteRefStyle(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewFootnote(
+				WithFootnoteRefStyle(FootnoteRefStyleSuperscript),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Footnote links should be rendered as superscripts",
+			Markdown: `text[^1]
+
+[^1]: footnote
+`,
+			Expected: `<p>text<sup id="fnref:1"><a href="#fn:1" class="footnote-ref" role="doc-noteref">1</a></sup></p>
+<div class="footnotes" role="doc-endnotes">
+<hr>
+<ol>
+<li id="fn:1">
+<p>footnote&#160;<a href="#fnref:1" class="footnote-backref" role="doc-backlink">&#x21a9;&#xfe0e;</a></p>
+</li>
+</ol>
+</div>`,
+		},
+		t,
+	)
+
+	markdown = goldmark.New(
+		goldmark.WithExtensions(
+			NewFootnote(
+				WithFootnoteRefStyle(FootnoteRefStyleParenthetical),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "Footnote links should be rendered in parentheses",
+			Markdown: `text[^1]
+
+[^1]: footnote
+`,
+			Expected: `<p>text<span id="fnref:1">(<a href="#fn:1" class="footnote-ref" role="doc-noteref">1</a>)</span></p>
+<div class="footnotes" role="doc-endnotes">
+<hr>
+<ol>
+<li id="fn:1">
+<p>footnote&#160;<a href="#fnref:1" class="footnote-backref" role="doc-backlink">&#x21a9;&#xfe0e;</a></p>
+</li>
+</ol>
+</div>`,
+		},
+		t,
+	)
+}
+
+func TestFootnoteContext(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewFootnote(
+				WithFootnoteContext(10),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Footnotes should have contexts of references",
+			Markdown: `As noted by *the author*[^1] in the preface.
+
+Short[^1].
+
+[^1]: footnote
+`,
+			Expected: `<p>As noted by <em>the author</em><sup id="fnref:1"><a href="#fn:1" class="footnote-ref" role="doc-noteref">1</a></sup> in the preface.</p>
+<p>Short<sup id="fnref1:1"><a href="#fn:1" class="footnote-ref" role="doc-noteref">1</a></sup>.</p>
+<div class="footnotes" role="doc-endnotes">
+<hr>
+<ol>
+<li id="fn:1">
+<p class="footnote-context">…the author in the pr…</p>
+<p class="footnote-context">Short.</p>
+<p>footnote&#160;<a href="#fnref:1" class="footnote-backref" role="doc-backlink">&#x21a9;&#xfe0e;</a>&#160;<a href="#fnref1:1" class="footnote-backref" role="doc-backlink">&#x21a9;&#xfe0e;</a></p>
+</li>
+</ol>
+</div>`,
+		},
+		t,
+	)
+}
+
+func TestFootnoteInTable(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Table,
+			Footnote,
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Footnote references in table cells",
+			Markdown: `[^1]: Defined before the table.
+
+| Name[^1] | Value |
+|:--|--:|
+| a[^2] | [^1] \| b |
+| c | [^3] |
+
+[^2]: Defined after the table.
+`,
+			Expected: `<table>
+<thead>
+<tr>
+<th style="text-align:left">Name<sup id="fnref:1"><a href="#fn:1" class="footnote-ref" role="doc-noteref">1</a></sup></th>
+<th style="text-align:right">Value</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td style="text-align:left">a<sup id="fnref:2"><a href="#fn:2" class="footnote-ref" role="doc-noteref">2</a></sup></td>
+<td style="text-align:right"><sup id="fnref1:1"><a href="#fn:1" class="footnote-ref" role="doc-noteref">1</a></sup> | b</td>
+</tr>
+<tr>
+<td style="text-align:left">c</td>
+<td style="text-align:right">[^3]</td>
+</tr>
+</tbody>
+</table>
+<div class="footnotes" role="doc-endnotes">
+<hr>
+<ol>
+<li id="fn:1">
+<p>Defined before the table.&#160;<a href="#fnref:1" class="footnote-backref" role="doc-backlink">&#x21a9;&#xfe0e;</a>&#160;<a href="#fnref1:1" class="footnote-backref" role="doc-backlink">&#x21a9;&#xfe0e;</a></p>
+</li>
+<li id="fn:2">
+<p>Defined after the table.&#160;<a href="#fnref:2" class="footnote-backref" role="doc-backlink">&#x21a9;&#xfe0e;</a></p>
+</li>
+</ol>
+</div>`,
+		},
+		t,
+	)
+}
+
+func TestFootnoteRefIDPrefix(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewFootnote(
+				WithFootnoteIDPrefix([]byte("doc1-")),
+				WithFootnoteRefIDPrefix([]byte("doc1-ref-")),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Footnote references should have their own id prefix",
+			Markdown: `Text.[^1]
+
+[^1]: Footnote.
+`,
+			Expected: `<p>Text.<sup id="doc1-ref-fnref:1"><a href="#doc1-fn:1" class="footnote-ref" role="doc-noteref">1</a></sup></p>
+<div class="footnotes" role="doc-endnotes">
+<hr>
+<ol>
+<li id="doc1-fn:1">
+<p>Footnote.&#160;<a href="#doc1-ref-fnref:1" class="footnote-backref" role="doc-backlink">&#x21a9;&#xfe0e;</a></p>
+</li>
+</ol>
+</div>`,
+		},
+		t,
+	)
+}
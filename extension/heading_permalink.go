@@ -0,0 +1,109 @@
+package extension
+
+import (
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+type headingPermalinkASTTransformer struct {
+	symbol    []byte
+	ariaLabel []byte
+}
+
+// NewHeadingPermalinkASTTransformer returns a parser.ASTTransformer that
+// appends ast.HeadingPermalink nodes to headings that have ids.
+func NewHeadingPermalinkASTTransformer(symbol, ariaLabel string) parser.ASTTransformer {
+	return &headingPermalinkASTTransformer{
+		symbol:    []byte(symbol),
+		ariaLabel: []byte(ariaLabel),
+	}
+}
+
+func (a *headingPermalinkASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	var headings []gast.Node
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if entering && n.Kind() == gast.KindHeading {
+			headings = append(headings, n)
+			return gast.WalkSkipChildren, nil
+		}
+		return gast.WalkContinue, nil
+	})
+	for _, h := range headings {
+		if _, ok := h.AttributeString("id"); !ok {
+			continue
+		}
+		h.AppendChild(h, ast.NewHeadingPermalink(a.symbol, a.ariaLabel))
+	}
+}
+
+// HeadingPermalinkHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders HeadingPermalink nodes.
+type HeadingPermalinkHTMLRenderer struct {
+	html.Config
+}
+
+// NewHeadingPermalinkHTMLRenderer returns a new HeadingPermalinkHTMLRenderer.
+func NewHeadingPermalinkHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &HeadingPermalinkHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *HeadingPermalinkHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindHeadingPermalink, r.renderHeadingPermalink)
+}
+
+func (r *HeadingPermalinkHTMLRenderer) renderHeadingPermalink(w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if !entering {
+		return gast.WalkContinue, nil
+	}
+	n := node.(*ast.HeadingPermalink)
+	id, ok := n.Parent().AttributeString("id")
+	if !ok {
+		return gast.WalkContinue, nil
+	}
+	_, _ = w.WriteString(` <a class="heading-permalink" href="#`)
+	_, _ = w.Write(util.EscapeHTML(util.URLEscape(id.([]byte), false)))
+	_, _ = w.WriteString(`" aria-label="`)
+	r.Writer.Write(w, n.AriaLabel)
+	_, _ = w.WriteString(`">`)
+	r.Writer.Write(w, n.Symbol)
+	_, _ = w.WriteString("</a>")
+	return gast.WalkContinue, nil
+}
+
+type headingPermalink struct {
+	symbol    string
+	ariaLabel string
+}
+
+// WithHeadingPermalink returns an extension that renders permalinks like
+// '<a class="heading-permalink" href="#id" aria-label="ariaLabel">symbol</a>'
+// at the end of headings that have ids.
+// Headings get ids by parser.WithAutoHeadingID or attributes.
+func WithHeadingPermalink(symbol, ariaLabel string) goldmark.Extender {
+	return &headingPermalink{
+		symbol:    symbol,
+		ariaLabel: ariaLabel,
+	}
+}
+
+func (e *headingPermalink) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewHeadingPermalinkASTTransformer(e.symbol, e.ariaLabel), 999),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewHeadingPermalinkHTMLRenderer(), 500),
+	))
+}
@@ -0,0 +1,50 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestHeadingPermalink(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+			parser.WithAttribute(),
+		),
+		goldmark.WithExtensions(
+			WithHeadingPermalink("&para;", "Permalink to this heading"),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "permalinks use ids of headings",
+			Markdown: `# Hello *world*
+
+## Custom {#custom-id}`,
+			Expected: `<h1 id="hello-world">Hello <em>world</em> <a class="heading-permalink" href="#hello-world" aria-label="Permalink to this heading">¶</a></h1>
+<h2 id="custom-id">Custom <a class="heading-permalink" href="#custom-id" aria-label="Permalink to this heading">¶</a></h2>`,
+		},
+		t,
+	)
+
+	markdown = goldmark.New(
+		goldmark.WithExtensions(
+			WithHeadingPermalink("#", "Permalink"),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "headings without ids do not have permalinks",
+			Markdown:    `# Hello`,
+			Expected:    `<h1>Hello</h1>`,
+		},
+		t,
+	)
+}
@@ -0,0 +1,203 @@
+package extension
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var commentDelimiter = []byte("%%")
+
+type inlineCommentParser struct {
+}
+
+var defaultInlineCommentParser = &inlineCommentParser{}
+
+// NewInlineCommentParser returns a new InlineParser that parses
+// inline comments like '%% comment %%'.
+// Comments must be closed in the same line.
+func NewInlineCommentParser() parser.InlineParser {
+	return defaultInlineCommentParser
+}
+
+func (s *inlineCommentParser) Trigger() []byte {
+	return []byte{'%'}
+}
+
+func (s *inlineCommentParser) Parse(parent gast.Node, block text.Reader, pc parser.Context) gast.Node {
+	line, segment := block.PeekLine()
+	if !bytes.HasPrefix(line, commentDelimiter) {
+		return nil
+	}
+	stop := bytes.Index(line[len(commentDelimiter):], commentDelimiter)
+	if stop < 0 {
+		return nil
+	}
+	start := segment.Start + len(commentDelimiter)
+	block.Advance(stop + len(commentDelimiter)*2)
+	return ast.NewComment(text.NewSegment(start, start+stop))
+}
+
+func (s *inlineCommentParser) CloseBlock(parent gast.Node, pc parser.Context) {
+	// nothing to do
+}
+
+// InlineCommentConfig struct holds options for the extension.
+type InlineCommentConfig struct {
+	html.Config
+
+	// HTMLComment indicates that comments should be rendered as HTML
+	// comments instead of being omitted.
+	HTMLComment bool
+}
+
+// InlineCommentOption interface is a functional option interface for the extension.
+type InlineCommentOption interface {
+	renderer.Option
+	// SetInlineCommentOption sets given option to the extension.
+	SetInlineCommentOption(*InlineCommentConfig)
+}
+
+// NewInlineCommentConfig returns a new Config with defaults.
+func NewInlineCommentConfig() InlineCommentConfig {
+	return InlineCommentConfig{
+		Config:      html.NewConfig(),
+		HTMLComment: false,
+	}
+}
+
+// SetOption implements renderer.SetOptioner.
+func (c *InlineCommentConfig) SetOption(name renderer.OptionName, value interface{}) {
+	switch name {
+	case optInlineCommentHTMLComment:
+		c.HTMLComment = value.(bool)
+	default:
+		c.Config.SetOption(name, value)
+	}
+}
+
+type withInlineCommentHTMLOptions struct {
+	value []html.Option
+}
+
+func (o *withInlineCommentHTMLOptions) SetConfig(c *renderer.Config) {
+	if o.value != nil {
+		for _, v := range o.value {
+			v.(renderer.Option).SetConfig(c)
+		}
+	}
+}
+
+func (o *withInlineCommentHTMLOptions) SetInlineCommentOption(c *InlineCommentConfig) {
+	if o.value != nil {
+		for _, v := range o.value {
+			v.SetHTMLOption(&c.Config)
+		}
+	}
+}
+
+// WithInlineCommentHTMLOptions is functional option that wraps goldmark HTMLRenderer options.
+func WithInlineCommentHTMLOptions(opts ...html.Option) InlineCommentOption {
+	return &withInlineCommentHTMLOptions{opts}
+}
+
+const optInlineCommentHTMLComment renderer.OptionName = "InlineCommentHTMLComment"
+
+type withInlineCommentHTMLComment struct {
+}
+
+func (o *withInlineCommentHTMLComment) SetConfig(c *renderer.Config) {
+	c.Options[optInlineCommentHTMLComment] = true
+}
+
+func (o *withInlineCommentHTMLComment) SetInlineCommentOption(c *InlineCommentConfig) {
+	c.HTMLComment = true
+}
+
+// WithInlineCommentHTMLComment is a functional option that renders
+// inline comments as HTML comments like '<!-- comment -->'.
+// Comments that start with '>' or '->', contain '--' or end with '-' are
+// omitted because they can not be safely rendered as HTML comments.
+func WithInlineCommentHTMLComment() InlineCommentOption {
+	return &withInlineCommentHTMLComment{}
+}
+
+// InlineCommentHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders Comment nodes.
+type InlineCommentHTMLRenderer struct {
+	InlineCommentConfig
+}
+
+// NewInlineCommentHTMLRenderer returns a new InlineCommentHTMLRenderer.
+func NewInlineCommentHTMLRenderer(opts ...InlineCommentOption) renderer.NodeRenderer {
+	r := &InlineCommentHTMLRenderer{
+		InlineCommentConfig: NewInlineCommentConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetInlineCommentOption(&r.InlineCommentConfig)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *InlineCommentHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindComment, r.renderComment)
+}
+
+func (r *InlineCommentHTMLRenderer) renderComment(w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if !entering || !r.HTMLComment {
+		return gast.WalkSkipChildren, nil
+	}
+	value := node.Text(source)
+	// comments that can not be safely contained in HTML comments are
+	// omitted. For example, '<!-->' closes the comment immediately.
+	if !isSafeHTMLCommentBody(value) {
+		return gast.WalkSkipChildren, nil
+	}
+	_, _ = w.WriteString("<!--")
+	_, _ = w.Write(value)
+	_, _ = w.WriteString("-->")
+	return gast.WalkSkipChildren, nil
+}
+
+// isSafeHTMLCommentBody returns true if the given value can be written
+// between '<!--' and '-->' as it is.
+func isSafeHTMLCommentBody(value []byte) bool {
+	return !bytes.HasPrefix(value, []byte(">")) &&
+		!bytes.HasPrefix(value, []byte("->")) &&
+		!bytes.Contains(value, []byte("--")) &&
+		!bytes.HasSuffix(value, []byte("-"))
+}
+
+type inlineComment struct {
+	options []InlineCommentOption
+}
+
+// InlineComments is an extension that allow you to use inline comments
+// like '%% comment %%' that are not rendered.
+var InlineComments = &inlineComment{
+	options: []InlineCommentOption{},
+}
+
+// NewInlineComments returns a new extension with given options.
+func NewInlineComments(opts ...InlineCommentOption) goldmark.Extender {
+	return &inlineComment{
+		options: opts,
+	}
+}
+
+func (e *inlineComment) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithInlineParsers(
+		util.Prioritized(NewInlineCommentParser(), 500),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewInlineCommentHTMLRenderer(e.options...), 500),
+	))
+}
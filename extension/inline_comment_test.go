@@ -0,0 +1,75 @@
+package extension
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/testutil"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestInlineComments(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			InlineComments,
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "comments are not rendered",
+			Markdown:    "foo %% hidden note %%bar 100% `%% code %%` %% unclosed",
+			Expected:    `<p>foo bar 100% <code>%% code %%</code> %% unclosed</p>`,
+		},
+		t,
+	)
+
+	source := []byte("foo %% hidden note %% bar")
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	var comment *ast.Comment
+	_ = gast.Walk(doc, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if c, ok := n.(*ast.Comment); ok && entering {
+			comment = c
+		}
+		return gast.WalkContinue, nil
+	})
+	if comment == nil {
+		t.Fatal("Comment node is not found")
+	}
+	if !bytes.Equal(comment.Text(source), []byte(" hidden note ")) {
+		t.Errorf("unexpected comment text: %q", comment.Text(source))
+	}
+
+	markdown = goldmark.New(
+		goldmark.WithExtensions(
+			NewInlineComments(
+				WithInlineCommentHTMLComment(),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "comments are rendered as HTML comments",
+			Markdown:    "foo %% note %% bar",
+			Expected:    `<p>foo <!-- note --> bar</p>`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          3,
+			Description: "comments that can not be safely rendered as HTML comments are omitted",
+			Markdown:    "a %%><script>alert(1)</script>%% b %%->x%% c %% note -- or ---> %% d %%x-%% e",
+			Expected:    `<p>a  b  c  d  e</p>`,
+		},
+		t,
+	)
+}
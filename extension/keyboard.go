@@ -0,0 +1,150 @@
+package extension
+
+import (
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// keyboardParser parses keyboard inputs enclosed by '++' like '++Ctrl++'.
+// '++' is used as a delimiter because '[[' is used by wikilinks and '|' is
+// used by tables. Keys of key combinations are separated by '+' like
+// '++Ctrl+Alt+Del++' as in PyMdown Extensions.
+type keyboardParser struct {
+}
+
+var defaultKeyboardParser = &keyboardParser{}
+
+// NewKeyboardParser return a new InlineParser that parses keyboard inputs
+// like '++Ctrl+C++'.
+func NewKeyboardParser() parser.InlineParser {
+	return defaultKeyboardParser
+}
+
+func (s *keyboardParser) Trigger() []byte {
+	return []byte{'+'}
+}
+
+func (s *keyboardParser) Parse(parent gast.Node, block text.Reader, pc parser.Context) gast.Node {
+	if block.PrecendingCharacter() == '+' {
+		return nil
+	}
+	line, segment := block.PeekLine()
+	if len(line) < 5 || line[1] != '+' || line[2] == '+' {
+		return nil
+	}
+	var keys []text.Segment
+	start := 2
+	i := 2
+	for ; i < len(line); i++ {
+		c := line[i]
+		if c == '\\' && i+1 < len(line) && util.IsPunct(line[i+1]) {
+			i++
+			continue
+		}
+		if c == '\n' {
+			return nil
+		}
+		if c != '+' {
+			continue
+		}
+		key := text.NewSegment(segment.Start+start, segment.Start+i)
+		if key.IsEmpty() || util.IsSpace(line[start]) || util.IsSpace(line[i-1]) {
+			return nil
+		}
+		keys = append(keys, key)
+		if i+1 < len(line) && line[i+1] == '+' {
+			break
+		}
+		start = i + 1
+	}
+	if i == len(line) {
+		return nil
+	}
+	node := ast.NewKeyboard()
+	if len(keys) == 1 {
+		node.AppendChild(node, gast.NewTextSegment(keys[0]))
+	} else {
+		for j, key := range keys {
+			if j != 0 {
+				node.AppendChild(node, gast.NewString([]byte("+")))
+			}
+			k := ast.NewKeyboard()
+			k.AppendChild(k, gast.NewTextSegment(key))
+			node.AppendChild(node, k)
+		}
+	}
+	block.Advance(i + 2)
+	return node
+}
+
+func (s *keyboardParser) CloseBlock(parent gast.Node, pc parser.Context) {
+	// nothing to do
+}
+
+// KeyboardHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders Keyboard nodes.
+type KeyboardHTMLRenderer struct {
+	html.Config
+}
+
+// NewKeyboardHTMLRenderer returns a new KeyboardHTMLRenderer.
+func NewKeyboardHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	r := &KeyboardHTMLRenderer{
+		Config: html.NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *KeyboardHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindKeyboard, r.renderKeyboard)
+}
+
+// KeyboardAttributeFilter defines attribute names which kbd elements can have.
+var KeyboardAttributeFilter = html.GlobalAttributeFilter
+
+func (r *KeyboardHTMLRenderer) renderKeyboard(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("<kbd")
+		if n.Attributes() != nil {
+			html.RenderAttributes(w, n, KeyboardAttributeFilter)
+		}
+		_ = w.WriteByte('>')
+	} else {
+		_, _ = w.WriteString("</kbd>")
+	}
+	return gast.WalkContinue, nil
+}
+
+type keyboard struct {
+	options []html.Option
+}
+
+// Keyboard is an extension that allow you to use keyboard inputs like
+// '++Ctrl+C++'.
+var Keyboard = &keyboard{}
+
+// NewKeyboard returns a new extension with given options.
+func NewKeyboard(opts ...html.Option) goldmark.Extender {
+	return &keyboard{
+		options: opts,
+	}
+}
+
+func (e *keyboard) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithInlineParsers(
+		util.Prioritized(NewKeyboardParser(), 500),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewKeyboardHTMLRenderer(e.options...), 500),
+	))
+}
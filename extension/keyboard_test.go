@@ -0,0 +1,17 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestKeyboard(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Keyboard,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/keyboard.txt", t, testutil.ParseCliCaseArg()...)
+}
@@ -3,18 +3,26 @@ package extension
 import (
 	"bytes"
 	"regexp"
+	"unicode"
 
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
 	"github.com/yuin/goldmark/text"
 	"github.com/yuin/goldmark/util"
 )
 
 var wwwURLRegxp = regexp.MustCompile(`^www\.[-a-zA-Z0-9@:%._\+~#=]{1,256}\.[a-z]+(?:[/#?][-a-zA-Z0-9@:%_\+.~#!?&/=\(\);,'">\^{}\[\]` + "`" + `]*)?`)
 
+var phoneRegexp = regexp.MustCompile(`^\+[0-9]{1,3}(?:[-.][0-9]{2,4}){2,4}`)
+
 var urlRegexp = regexp.MustCompile(`^(?:http|https|ftp)://[-a-zA-Z0-9@:%._\+~#=]{1,256}\.[a-z]+(?::\d+)?(?:[/#?][-a-zA-Z0-9@:%_+.~#$!?&/=\(\);,'">\^{}\[\]` + "`" + `]*)?`)
 
+// schemeURLRegexp matches URLs of arbitrary schemes like 'steam://run/10' and
+// 'mailto:foo@example.com'.
+var schemeURLRegexp = regexp.MustCompile(`^[a-zA-Z][-a-zA-Z0-9+.]*:(?://)?[a-zA-Z0-9][-a-zA-Z0-9@:%_+.~#$!?&/=\(\);,'\^{}\[\]` + "`" + `]*`)
+
 // An LinkifyConfig struct is a data structure that holds configuration of the
 // Linkify extension.
 type LinkifyConfig struct {
@@ -22,6 +30,16 @@ type LinkifyConfig struct {
 	URLRegexp        *regexp.Regexp
 	WWWRegexp        *regexp.Regexp
 	EmailRegexp      *regexp.Regexp
+	PhoneFormatter   func(number []byte) []byte
+	Title            func(url []byte) []byte
+
+	// HideScheme indicates that schemes like 'https://' and 'mailto:' of
+	// autolinks should not be displayed. Destinations keep the schemes.
+	HideScheme bool
+
+	// BreakHints indicates that '<wbr>' elements should be inserted after
+	// '/', '?' and '&' in displayed texts of autolinks.
+	BreakHints bool
 }
 
 const (
@@ -29,6 +47,10 @@ const (
 	optLinkifyURLRegexp        parser.OptionName = "LinkifyURLRegexp"
 	optLinkifyWWWRegexp        parser.OptionName = "LinkifyWWWRegexp"
 	optLinkifyEmailRegexp      parser.OptionName = "LinkifyEmailRegexp"
+	optLinkifyPhoneFormatter   parser.OptionName = "LinkifyPhoneFormatter"
+	optLinkifyTitle            parser.OptionName = "LinkifyTitle"
+	optLinkifyHideScheme       parser.OptionName = "LinkifyHideScheme"
+	optLinkifyBreakHints       parser.OptionName = "LinkifyBreakHints"
 )
 
 // SetOption implements SetOptioner.
@@ -42,6 +64,14 @@ func (c *LinkifyConfig) SetOption(name parser.OptionName, value interface{}) {
 		c.WWWRegexp = value.(*regexp.Regexp)
 	case optLinkifyEmailRegexp:
 		c.EmailRegexp = value.(*regexp.Regexp)
+	case optLinkifyPhoneFormatter:
+		c.PhoneFormatter = value.(func([]byte) []byte)
+	case optLinkifyTitle:
+		c.Title = value.(func([]byte) []byte)
+	case optLinkifyHideScheme:
+		c.HideScheme = value.(bool)
+	case optLinkifyBreakHints:
+		c.BreakHints = value.(bool)
 	}
 }
 
@@ -133,6 +163,138 @@ func WithLinkifyEmailRegexp(value *regexp.Regexp) LinkifyOption {
 	}
 }
 
+type withLinkifyPhone struct {
+	value func(number []byte) []byte
+}
+
+func (o *withLinkifyPhone) SetParserOption(c *parser.Config) {
+	c.Options[optLinkifyPhoneFormatter] = o.value
+}
+
+func (o *withLinkifyPhone) SetLinkifyOption(p *LinkifyConfig) {
+	p.PhoneFormatter = o.value
+}
+
+// WithLinkifyPhone is a functional option that enables linking
+// international phone numbers like '+1-555-123-4567' as 'tel:' links.
+// A phone number must start with '+' and its digits must be grouped by
+// '-' or '.'. Spaces are not allowed as separators because numbers like
+// '+10 20 30' in texts are not phone numbers in most cases.
+// format converts a matched phone number into a 'tel:' URI body.
+// If format is nil, all characters except '+' and digits are removed.
+func WithLinkifyPhone(format func(number []byte) []byte) LinkifyOption {
+	if format == nil {
+		format = formatPhoneNumber
+	}
+	return &withLinkifyPhone{
+		value: format,
+	}
+}
+
+type withLinkifyTitle struct {
+	value func(url []byte) []byte
+}
+
+func (o *withLinkifyTitle) SetParserOption(c *parser.Config) {
+	c.Options[optLinkifyTitle] = o.value
+}
+
+func (o *withLinkifyTitle) SetLinkifyOption(p *LinkifyConfig) {
+	p.Title = o.value
+}
+
+// WithLinkifyTitle is a functional option that adds title attributes to
+// autolinks. title receives the URL of an autolink and returns the title.
+// If title returns nil, no title attribute is added.
+func WithLinkifyTitle(title func(url []byte) []byte) LinkifyOption {
+	return &withLinkifyTitle{
+		value: title,
+	}
+}
+
+type withLinkifyHideScheme struct {
+}
+
+func (o *withLinkifyHideScheme) SetParserOption(c *parser.Config) {
+	c.Options[optLinkifyHideScheme] = true
+}
+
+func (o *withLinkifyHideScheme) SetLinkifyOption(p *LinkifyConfig) {
+	p.HideScheme = true
+}
+
+// WithLinkifyHideScheme is a functional option that hides schemes of
+// autolinks in displayed texts. 'https://example.com' is displayed as
+// 'example.com' and 'mailto:foo@example.com' is displayed as
+// 'foo@example.com'. Destinations of autolinks keep the schemes.
+func WithLinkifyHideScheme() LinkifyOption {
+	return &withLinkifyHideScheme{}
+}
+
+type withLinkifyBreakHints struct {
+}
+
+func (o *withLinkifyBreakHints) SetParserOption(c *parser.Config) {
+	c.Options[optLinkifyBreakHints] = true
+}
+
+func (o *withLinkifyBreakHints) SetLinkifyOption(p *LinkifyConfig) {
+	p.BreakHints = true
+}
+
+// WithLinkifyBreakHints is a functional option that inserts '<wbr>'
+// elements after '/', '?' and '&' in displayed texts of autolinks, so long
+// URLs can be wrapped by browsers.
+func WithLinkifyBreakHints() LinkifyOption {
+	return &withLinkifyBreakHints{}
+}
+
+var (
+	schemeSeparator = []byte("://")
+	protoMailto     = []byte("mailto:")
+)
+
+// hideScheme returns an autolink that displays the given URL without the
+// scheme.
+func hideScheme(typ ast.AutoLinkType, protocol []byte, value text.Segment, source []byte) (ast.AutoLinkType, []byte, text.Segment) {
+	if typ != ast.AutoLinkURL || protocol != nil {
+		return typ, protocol, value
+	}
+	v := value.Value(source)
+	if i := bytes.Index(v, schemeSeparator); i > 0 && len(v) > i+len(schemeSeparator) {
+		return typ, v[:i], text.NewSegment(value.Start+i+len(schemeSeparator), value.Stop)
+	}
+	if bytes.HasPrefix(v, protoMailto) && len(v) > len(protoMailto) {
+		return ast.AutoLinkEmail, nil, text.NewSegment(value.Start+len(protoMailto), value.Stop)
+	}
+	return typ, protocol, value
+}
+
+func formatPhoneNumber(number []byte) []byte {
+	ret := make([]byte, 0, len(number))
+	for _, c := range number {
+		if c == '+' || util.IsNumeric(c) {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// isPhoneNumberEnd returns true if a phone number can end before the given
+// bytes.
+func isPhoneNumberEnd(rest []byte) bool {
+	if len(rest) == 0 {
+		return true
+	}
+	if util.IsAlphaNumeric(rest[0]) {
+		return false
+	}
+	if rest[0] == '-' || rest[0] == '.' {
+		return len(rest) == 1 || !util.IsNumeric(rest[1])
+	}
+	return true
+}
+
 type linkifyParser struct {
 	LinkifyConfig
 }
@@ -155,6 +317,10 @@ func NewLinkifyParser(opts ...LinkifyOption) parser.InlineParser {
 
 func (s *linkifyParser) Trigger() []byte {
 	// ' ' indicates any white spaces and a line head
+	if s.LinkifyConfig.PhoneFormatter != nil {
+		// phone numbers start with '+' that is a punctuation
+		return []byte{' ', '*', '_', '~', '(', '+'}
+	}
 	return []byte{' ', '*', '_', '~', '('}
 }
 
@@ -165,6 +331,19 @@ var (
 	domainWWW  = []byte("www.")
 )
 
+// urlRegexpOf returns a regexp that matches URLs of the given protocol.
+// URLs of protocols other than http, https and ftp are matched by a generic
+// pattern unless the URLRegexp option is given.
+func (s *linkifyParser) urlRegexpOf(protocol []byte) *regexp.Regexp {
+	if s.LinkifyConfig.URLRegexp != urlRegexp {
+		return s.LinkifyConfig.URLRegexp
+	}
+	if bytes.Equal(protocol, protoHTTP) || bytes.Equal(protocol, protoHTTPS) || bytes.Equal(protocol, protoFTP) {
+		return urlRegexp
+	}
+	return schemeURLRegexp
+}
+
 func (s *linkifyParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
 	if pc.IsInLinkLabel() {
 		return nil
@@ -190,7 +369,7 @@ func (s *linkifyParser) Parse(parent ast.Node, block text.Reader, pc parser.Cont
 	} else {
 		for _, prefix := range s.LinkifyConfig.AllowedProtocols {
 			if bytes.HasPrefix(line, prefix) {
-				m = s.LinkifyConfig.URLRegexp.FindSubmatchIndex(line)
+				m = s.urlRegexpOf(prefix).FindSubmatchIndex(line)
 				break
 			}
 		}
@@ -206,7 +385,10 @@ func (s *linkifyParser) Parse(parent ast.Node, block text.Reader, pc parser.Cont
 		lastChar := line[m[1]-1]
 		if lastChar == '.' {
 			m[1]--
-		} else if lastChar == ')' {
+			// a URL followed by ').' like '(see http://example.com).'
+			lastChar = line[m[1]-1]
+		}
+		if lastChar == ')' {
 			closing := 0
 			for i := m[1] - 1; i >= m[0]; i-- {
 				if line[i] == ')' {
@@ -233,6 +415,22 @@ func (s *linkifyParser) Parse(parent ast.Node, block text.Reader, pc parser.Cont
 			}
 		}
 	}
+	if c == '+' && !unicode.IsSpace(block.PrecendingCharacter()) {
+		return nil
+	}
+	if m == nil && s.LinkifyConfig.PhoneFormatter != nil && len(line) > 0 && line[0] == '+' {
+		if pm := phoneRegexp.FindIndex(line); pm != nil && isPhoneNumberEnd(line[pm[1]:]) {
+			if consumes != 0 {
+				s := segment.WithStop(segment.Start + 1)
+				ast.MergeOrAppendTextSegment(parent, s)
+			}
+			block.Advance(consumes + pm[1])
+			link := ast.NewLink()
+			link.Destination = append([]byte("tel:"), s.LinkifyConfig.PhoneFormatter(line[:pm[1]])...)
+			link.AppendChild(link, ast.NewTextSegment(text.NewSegment(start, start+pm[1])))
+			return link
+		}
+	}
 	if m == nil {
 		if len(line) > 0 && util.IsPunct(line[0]) {
 			return nil
@@ -286,9 +484,18 @@ endfor:
 	i++
 	consumes += i
 	block.Advance(consumes)
-	n := ast.NewTextSegment(text.NewSegment(start, start+i))
+	value := text.NewSegment(start, start+i)
+	if s.LinkifyConfig.HideScheme {
+		typ, protocol, value = hideScheme(typ, protocol, value, block.Source())
+	}
+	n := ast.NewTextSegment(value)
 	link := ast.NewAutoLink(typ, n)
 	link.Protocol = protocol
+	if s.LinkifyConfig.Title != nil {
+		if title := s.LinkifyConfig.Title(link.URL(block.Source())); title != nil {
+			link.SetAttributeString("title", title)
+		}
+	}
 	return link
 }
 
@@ -315,4 +522,11 @@ func (e *linkify) Extend(m goldmark.Markdown) {
 			util.Prioritized(NewLinkifyParser(e.options...), 999),
 		),
 	)
+	config := LinkifyConfig{}
+	for _, opt := range e.options {
+		opt.SetLinkifyOption(&config)
+	}
+	if config.BreakHints {
+		m.Renderer().AddOptions(html.WithAutoLinkBreakHints())
+	}
 }
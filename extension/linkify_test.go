@@ -1,6 +1,8 @@
 package extension
 
 import (
+	"bytes"
+	"net/url"
 	"regexp"
 	"testing"
 
@@ -49,6 +51,32 @@ func TestLinkifyWithAllowedProtocols(t *testing.T) {
 	)
 }
 
+func TestLinkifyWithCustomSchemes(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewLinkify(
+				WithLinkifyAllowedProtocols([][]byte{
+					[]byte("http:"),
+					[]byte("https:"),
+					[]byte("ftp:"),
+					[]byte("mailto:"),
+					[]byte("steam:"),
+				}),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "URLs of custom schemes are linked without a URL regexp",
+			Markdown:    `Run steam://run/440. Mail mailto:foo@example.com (or ftp://example.com/a) or (steam://open/games). See https://example.com/, www.example.org and gopher://example.com.`,
+			Expected:    `<p>Run <a href="steam://run/440">steam://run/440</a>. Mail <a href="mailto:foo@example.com">mailto:foo@example.com</a> (or <a href="ftp://example.com/a">ftp://example.com/a</a>) or (<a href="steam://open/games">steam://open/games</a>). See <a href="https://example.com/">https://example.com/</a>, <a href="http://www.example.org">www.example.org</a> and gopher://example.com.</p>`,
+		},
+		t,
+	)
+}
+
 func TestLinkifyWithWWWRegexp(t *testing.T) {
 	markdown := goldmark.New(
 		goldmark.WithRendererOptions(
@@ -98,3 +126,168 @@ func TestLinkifyWithEmailRegexp(t *testing.T) {
 		t,
 	)
 }
+
+func TestLinkifyPhone(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+		),
+		goldmark.WithExtensions(
+			NewLinkify(
+				WithLinkifyPhone(nil),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:       1,
+			Markdown: "Call +1-555-123-4567 or +81.30.1234.5678.\n+1 (555) 123-4567 is not linked, 1+1-555-123-4567 neither.",
+			Expected: `<p>Call <a href="tel:+15551234567">+1-555-123-4567</a> or <a href="tel:+813012345678">+81.30.1234.5678</a>.
++1 (555) 123-4567 is not linked, 1+1-555-123-4567 neither.</p>`,
+		},
+		t,
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:       2,
+			Markdown: `Order 12345 costs +5 and 555-123-4567 is not linked, nor is +1-555-123-4567x or +1-2.`,
+			Expected: `<p>Order 12345 costs +5 and 555-123-4567 is not linked, nor is +1-555-123-4567x or +1-2.</p>`,
+		},
+		t,
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:       3,
+			Markdown: `The score was +10 20 30 and +44 20 7946 0958 is not linked.`,
+			Expected: `<p>The score was +10 20 30 and +44 20 7946 0958 is not linked.</p>`,
+		},
+		t,
+	)
+
+	markdown = goldmark.New(
+		goldmark.WithExtensions(
+			NewLinkify(
+				WithLinkifyPhone(func(number []byte) []byte {
+					return bytes.ReplaceAll(number, []byte("."), []byte("-"))
+				}),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:       4,
+			Markdown: `+44.20.7946.0958`,
+			Expected: `<p><a href="tel:+44-20-7946-0958">+44.20.7946.0958</a></p>`,
+		},
+		t,
+	)
+}
+
+func TestLinkifyHideScheme(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewLinkify(
+				WithLinkifyHideScheme(),
+				WithLinkifyAllowedProtocols([][]byte{
+					[]byte("http:"),
+					[]byte("https:"),
+					[]byte("mailto:"),
+				}),
+				WithLinkifyURLRegexp(
+					regexp.MustCompile(`^(?:https?://|mailto:)[-a-zA-Z0-9@:%._\+~#=/?&]+`),
+				),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "schemes are hidden in displayed texts",
+			Markdown:    `Visit https://example.com/path, www.example.org, mailto:foo@example.com and bar@example.com.`,
+			Expected:    `<p>Visit <a href="https://example.com/path">example.com/path</a>, <a href="http://www.example.org">www.example.org</a>, <a href="mailto:foo@example.com">foo@example.com</a> and <a href="mailto:bar@example.com">bar@example.com</a>.</p>`,
+		},
+		t,
+	)
+}
+
+func TestLinkifyWithTitle(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewLinkify(
+				WithLinkifyTitle(func(dest []byte) []byte {
+					u, err := url.Parse(string(dest))
+					if err != nil || u.Host == "" {
+						return nil
+					}
+					return []byte(u.Host)
+				}),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:       1,
+			Markdown: `Visit https://example.com/path, www.example.org and foo@example.com.`,
+			Expected: `<p>Visit <a href="https://example.com/path" title="example.com">https://example.com/path</a>, <a href="http://www.example.org" title="www.example.org">www.example.org</a> and <a href="mailto:foo@example.com">foo@example.com</a>.</p>`,
+		},
+		t,
+	)
+}
+
+func TestLinkifyBreakHints(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewLinkify(
+				WithLinkifyBreakHints(),
+			),
+		),
+	)
+	cases := []testutil.MarkdownTestCase{
+		{
+			No:          1,
+			Description: "break hints are inserted after '/', '?' and '&'",
+			Markdown:    `See https://example.com/very/long/path?a=1&b=2 and www.example.org/x/.`,
+			Expected:    `<p>See <a href="https://example.com/very/long/path?a=1&amp;b=2">https://<wbr>example.com/<wbr>very/<wbr>long/<wbr>path?<wbr>a=1&amp;<wbr>b=2</a> and <a href="http://www.example.org/x/">www.example.org/<wbr>x/</a>.</p>`,
+		},
+		{
+			No:          2,
+			Description: "break hints are inserted into CommonMark autolinks",
+			Markdown:    `<https://example.com/a/b> and <foo@example.com>`,
+			Expected:    `<p><a href="https://example.com/a/b">https://<wbr>example.com/<wbr>a/<wbr>b</a> and <a href="mailto:foo@example.com">foo@example.com</a></p>`,
+		},
+	}
+	for _, c := range cases {
+		testutil.DoTestCase(markdown, c, t)
+	}
+}
+
+func TestLinkifyPrintURLs(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithPrintURLs(func(dest []byte) bool {
+				return bytes.HasPrefix(dest, []byte("http"))
+			}),
+		),
+		goldmark.WithExtensions(
+			NewLinkify(
+				WithLinkifyBreakHints(),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "URLs are rendered after linkified URLs",
+			Markdown:    `See www.example.com/a and <https://example.org/b>.`,
+			Expected:    `<p>See <a href="http://www.example.com/a">www.example.com/<wbr>a</a><span class="print-url">http://www.example.com/a</span> and <a href="https://example.org/b">https://<wbr>example.org/<wbr>b</a><span class="print-url">https://example.org/b</span>.</p>`,
+		},
+		t,
+	)
+}
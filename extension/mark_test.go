@@ -0,0 +1,17 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestMark(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Mark,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/mark.txt", t, testutil.ParseCliCaseArg()...)
+}
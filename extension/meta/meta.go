@@ -0,0 +1,191 @@
+// Package meta is an extension for goldmark that parses YAML metadata
+// (frontmatter) at the beginning of documents.
+package meta
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var rawKey = parser.NewContextKey()
+var dataKey = parser.NewContextKey()
+var errorKey = parser.NewContextKey()
+
+// Get returns YAML metadata of the document.
+// Get returns nil if the document does not have metadata or the metadata
+// can not be parsed.
+func Get(pc parser.Context) map[string]interface{} {
+	v, err := TryGet(pc)
+	if err != nil {
+		return nil
+	}
+	return v
+}
+
+// TryGet tries to get YAML metadata of the document.
+// If the metadata can not be parsed, TryGet returns an error.
+func TryGet(pc parser.Context) (map[string]interface{}, error) {
+	if v := pc.Get(errorKey); v != nil {
+		return nil, v.(error)
+	}
+	if v := pc.Get(dataKey); v != nil {
+		return v.(map[string]interface{}), nil
+	}
+	raw := Raw(pc)
+	if raw == nil {
+		return nil, nil
+	}
+	data, err := parseYAML(raw)
+	if err != nil {
+		pc.Set(errorKey, err)
+		return nil, err
+	}
+	pc.Set(dataKey, data)
+	return data, nil
+}
+
+// Raw returns raw bytes of YAML metadata of the document without fences.
+// Raw returns nil if the document does not have metadata.
+func Raw(pc parser.Context) []byte {
+	if v := pc.Get(rawKey); v != nil {
+		return v.([]byte)
+	}
+	return nil
+}
+
+// kindMetadata is a NodeKind of the metadata node.
+var kindMetadata = gast.NewNodeKind("Metadata")
+
+// metadata represents a frontmatter block. metadata nodes are removed from
+// the document after they are parsed.
+type metadata struct {
+	gast.BaseBlock
+}
+
+func (n *metadata) Kind() gast.NodeKind {
+	return kindMetadata
+}
+
+func (n *metadata) IsRaw() bool {
+	return true
+}
+
+func (n *metadata) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+func isOpeningFence(line []byte) bool {
+	return bytes.Equal(util.TrimRightSpace(line), []byte("---"))
+}
+
+func isClosingFence(line []byte) bool {
+	line = util.TrimRightSpace(line)
+	return bytes.Equal(line, []byte("---")) || bytes.Equal(line, []byte("..."))
+}
+
+// hasClosingFence returns true if source has a closing fence after pos.
+func hasClosingFence(source []byte, pos int) bool {
+	for pos < len(source) {
+		stop := bytes.IndexByte(source[pos:], '\n')
+		if stop < 0 {
+			stop = len(source)
+		} else {
+			stop += pos + 1
+		}
+		if isClosingFence(source[pos:stop]) {
+			return true
+		}
+		pos = stop
+	}
+	return false
+}
+
+// advanceLine advances the reader to the end of the current line.
+func advanceLine(reader text.Reader, line []byte, segment text.Segment) {
+	newline := 1
+	if line[len(line)-1] != '\n' {
+		newline = 0
+	}
+	reader.Advance(segment.Len() - newline)
+}
+
+type metaParser struct {
+}
+
+var defaultMetaParser = &metaParser{}
+
+// NewParser returns a new parser.BlockParser that can parse YAML metadata
+// blocks. Metadata blocks must start at the very beginning of the document.
+func NewParser() parser.BlockParser {
+	return defaultMetaParser
+}
+
+func (b *metaParser) Trigger() []byte {
+	return []byte{'-'}
+}
+
+func (b *metaParser) Open(parent gast.Node, reader text.Reader, pc parser.Context) (gast.Node, parser.State) {
+	if parent.Kind() != gast.KindDocument || parent.HasChildren() {
+		return nil, parser.NoChildren
+	}
+	line, segment := reader.PeekLine()
+	if segment.Start != 0 || !isOpeningFence(line) {
+		return nil, parser.NoChildren
+	}
+	// an unclosed fence is a thematic break or a setext heading underline.
+	if !hasClosingFence(reader.Source(), segment.Stop) {
+		return nil, parser.NoChildren
+	}
+	advanceLine(reader, line, segment)
+	return &metadata{}, parser.NoChildren
+}
+
+func (b *metaParser) Continue(node gast.Node, reader text.Reader, pc parser.Context) parser.State {
+	line, segment := reader.PeekLine()
+	if isClosingFence(line) {
+		advanceLine(reader, line, segment)
+		return parser.Close
+	}
+	node.Lines().Append(segment)
+	advanceLine(reader, line, segment)
+	return parser.Continue | parser.NoChildren
+}
+
+func (b *metaParser) Close(node gast.Node, reader text.Reader, pc parser.Context) {
+	var buf bytes.Buffer
+	lines := node.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		buf.Write(segment.Value(reader.Source()))
+	}
+	pc.Set(rawKey, buf.Bytes())
+	node.Parent().RemoveChild(node.Parent(), node)
+}
+
+func (b *metaParser) CanInterruptParagraph() bool {
+	return false
+}
+
+func (b *metaParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+type meta struct {
+}
+
+// Meta is an extension that parses YAML metadata at the beginning of
+// documents. Metadata can be accessed via Get after documents are parsed.
+var Meta = &meta{}
+
+func (e *meta) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(
+			util.Prioritized(NewParser(), 0),
+		),
+	)
+}
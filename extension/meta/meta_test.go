@@ -0,0 +1,193 @@
+package meta
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"reflect"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestMeta(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Meta,
+		),
+	)
+	source := `---
+title: Hello, world!   # a comment
+draft: false
+weight: 10
+ratio: 0.5
+tags: [go, "markdown"]
+author:
+  name: John
+  email: 'john@example.com'
+aliases:
+- /a
+- /b
+items:
+  - name: x
+    value: 1
+  - name: y
+empty:
+description: |
+  first line
+  second line
+---
+# Hello
+`
+	context := parser.NewContext()
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(source), &buf, parser.WithContext(context)); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "<h1>Hello</h1>\n" {
+		t.Errorf("metadata must be removed from the output, but got %q", buf.String())
+	}
+	expected := map[string]interface{}{
+		"title":  "Hello, world!",
+		"draft":  false,
+		"weight": 10,
+		"ratio":  0.5,
+		"tags":   []interface{}{"go", "markdown"},
+		"author": map[string]interface{}{
+			"name":  "John",
+			"email": "john@example.com",
+		},
+		"aliases": []interface{}{"/a", "/b"},
+		"items": []interface{}{
+			map[string]interface{}{"name": "x", "value": 1},
+			map[string]interface{}{"name": "y"},
+		},
+		"empty":       nil,
+		"description": "first line\nsecond line\n",
+	}
+	data := Get(context)
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("expected %#v, but got %#v", expected, data)
+	}
+}
+
+func TestMetaInvalid(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Meta,
+		),
+	)
+	context := parser.NewContext()
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte("---\ntitle: [a, b\n---\ntext\n"), &buf, parser.WithContext(context)); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "<p>text</p>\n" {
+		t.Errorf("metadata must be removed from the output, but got %q", buf.String())
+	}
+	if _, err := TryGet(context); err == nil {
+		t.Error("TryGet must return an error for invalid metadata")
+	}
+	if v := Get(context); v != nil {
+		t.Errorf("Get must return nil for invalid metadata, but got %#v", v)
+	}
+	if string(Raw(context)) != "title: [a, b\n" {
+		t.Errorf("unexpected raw metadata %q", Raw(context))
+	}
+}
+
+func TestMetaFences(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Meta,
+		),
+	)
+	cases := []testutil.MarkdownTestCase{
+		{
+			No:          1,
+			Description: "Thematic breaks after the beginning of the document are not metadata",
+			Markdown: `text
+
+---
+title: a
+---`,
+			Expected: `<p>text</p>
+<hr>
+<h2>title: a</h2>`,
+		},
+		{
+			No:          2,
+			Description: "Unclosed fences are not metadata",
+			Markdown: `---
+text`,
+			Expected: `<hr>
+<p>text</p>`,
+		},
+		{
+			No:          3,
+			Description: "Metadata can be closed with '...'",
+			Markdown: `---
+title: a
+...
+text`,
+			Expected: `<p>text</p>`,
+		},
+		{
+			No:          4,
+			Description: "Metadata without trailing newlines",
+			Markdown: `---
+title: a
+---`,
+			Expected: ``,
+		},
+	}
+	for _, c := range cases {
+		testutil.DoTestCase(markdown, c, t)
+	}
+
+	context := parser.NewContext()
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte("text\n\n---\ntitle: a\n---\n"), &buf, parser.WithContext(context)); err != nil {
+		t.Fatal(err)
+	}
+	if v := Get(context); v != nil {
+		t.Errorf("metadata must not be found, but got %#v", v)
+	}
+}
+
+type mapCache struct {
+	entries map[[sha256.Size]byte]*parser.CacheEntry
+}
+
+func (c *mapCache) Get(hash [sha256.Size]byte) (*parser.CacheEntry, bool) {
+	e, ok := c.entries[hash]
+	return e, ok
+}
+
+func (c *mapCache) Put(hash [sha256.Size]byte, entry *parser.CacheEntry) {
+	c.entries[hash] = entry
+}
+
+func TestMetaWithCache(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithParserOptions(
+			parser.WithCache(&mapCache{entries: map[[sha256.Size]byte]*parser.CacheEntry{}}),
+		),
+		goldmark.WithExtensions(
+			Meta,
+		),
+	)
+	source := []byte("---\ntitle: Hello\n---\n# Hello\n")
+	for i := 0; i < 2; i++ {
+		context := parser.NewContext()
+		var buf bytes.Buffer
+		if err := markdown.Convert(source, &buf, parser.WithContext(context)); err != nil {
+			t.Fatal(err)
+		}
+		metaData := Get(context)
+		if metaData["title"] != "Hello" {
+			t.Errorf("%d: metadata must be restored from the cache, but got %v", i, metaData)
+		}
+	}
+}
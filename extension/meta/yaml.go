@@ -0,0 +1,487 @@
+package meta
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseYAML parses a subset of YAML that is commonly used in frontmatters:
+// block mappings, block sequences, flow collections, quoted and plain
+// scalars and literal(|) and folded(>) block scalars.
+// Anchors, aliases, tags and multi-line flow collections are not supported.
+func parseYAML(source []byte) (map[string]interface{}, error) {
+	p := &yamlParser{
+		lines: strings.Split(strings.ReplaceAll(string(source), "\r\n", "\n"), "\n"),
+	}
+	indent, _, ok := p.peek()
+	if !ok {
+		return map[string]interface{}{}, nil
+	}
+	v, err := p.parseNode(indent)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, ok := p.peek(); ok {
+		return nil, p.errorf("unexpected indentation")
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("yaml: metadata must be a mapping")
+	}
+	return m, nil
+}
+
+type yamlParser struct {
+	lines []string
+	pos   int
+}
+
+func (p *yamlParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("yaml: line %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+// peek returns the indentation and the text of the next line that is
+// neither blank nor a comment.
+func (p *yamlParser) peek() (int, string, bool) {
+	for ; p.pos < len(p.lines); p.pos++ {
+		line := p.lines[p.pos]
+		text := stripComment(strings.TrimLeft(line, " \t"))
+		if len(text) == 0 {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if line[indent] == '\t' {
+			// tabs can not be used for indentation.
+			return -1, text, true
+		}
+		return indent, text, true
+	}
+	return 0, "", false
+}
+
+func isSequenceEntry(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func (p *yamlParser) parseNode(indent int) (interface{}, error) {
+	i, text, ok := p.peek()
+	if !ok || i < indent {
+		return nil, nil
+	}
+	if i < 0 {
+		return nil, p.errorf("tabs can not be used for indentation")
+	}
+	if isSequenceEntry(text) {
+		return p.parseSequence(i)
+	}
+	return p.parseMapping(i)
+}
+
+func (p *yamlParser) parseSequence(indent int) ([]interface{}, error) {
+	list := []interface{}{}
+	for {
+		i, text, ok := p.peek()
+		if !ok || i < indent || (i == indent && !isSequenceEntry(text)) {
+			// a sequence that has the same indentation as its key ends
+			// with the next key.
+			return list, nil
+		}
+		if i > indent {
+			return nil, p.errorf("unexpected indentation")
+		}
+		rest := strings.TrimLeft(text[1:], " ")
+		if len(rest) == 0 {
+			p.pos++
+			v, err := p.parseNode(indent + 1)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+			continue
+		}
+		if isSequenceEntry(rest) || isMappingEntry(rest) {
+			// '- key: value' starts a mapping that is indented by the
+			// position of the key.
+			offset := indent + len(text) - len(rest)
+			p.lines[p.pos] = strings.Repeat(" ", offset) + rest
+			v, err := p.parseNode(offset)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+			continue
+		}
+		v, err := p.parseValue(rest, indent)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+}
+
+func (p *yamlParser) parseMapping(indent int) (map[string]interface{}, error) {
+	m := map[string]interface{}{}
+	for {
+		i, text, ok := p.peek()
+		if !ok || i < indent {
+			return m, nil
+		}
+		if i > indent {
+			return nil, p.errorf("unexpected indentation")
+		}
+		if isSequenceEntry(text) {
+			return nil, p.errorf("a sequence entry is not allowed in a mapping")
+		}
+		key, rest, ok := splitMappingEntry(text)
+		if !ok {
+			return nil, p.errorf("a mapping entry is expected")
+		}
+		name, err := parseScalar(key)
+		if err != nil {
+			return nil, p.errorf("%s", err)
+		}
+		if len(rest) != 0 {
+			v, err := p.parseValue(rest, indent)
+			if err != nil {
+				return nil, err
+			}
+			m[fmt.Sprint(name)] = v
+			continue
+		}
+		p.pos++
+		var v interface{}
+		if i, text, ok := p.peek(); ok && i == indent && isSequenceEntry(text) {
+			// sequences can have the same indentation as their keys.
+			v, err = p.parseSequence(indent)
+		} else {
+			v, err = p.parseNode(indent + 1)
+		}
+		if err != nil {
+			return nil, err
+		}
+		m[fmt.Sprint(name)] = v
+	}
+}
+
+// parseValue parses a value that starts at the current line.
+func (p *yamlParser) parseValue(text string, indent int) (interface{}, error) {
+	if len(text) != 0 && (text[0] == '|' || text[0] == '>') {
+		chomping := strings.TrimSpace(text[1:])
+		if chomping != "" && chomping != "-" && chomping != "+" {
+			return nil, p.errorf("unsupported block scalar indicator %q", text)
+		}
+		p.pos++
+		return p.parseBlockScalar(text[0] == '>', chomping, indent), nil
+	}
+	p.pos++
+	v, err := parseScalar(text)
+	if err != nil {
+		p.pos--
+		return nil, p.errorf("%s", err)
+	}
+	return v, nil
+}
+
+func (p *yamlParser) parseBlockScalar(folded bool, chomping string, indent int) string {
+	var lines []string
+	blockIndent := -1
+	for ; p.pos < len(p.lines); p.pos++ {
+		line := p.lines[p.pos]
+		text := strings.TrimLeft(line, " ")
+		i := len(line) - len(text)
+		if len(text) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		if i <= indent || (blockIndent > -1 && i < blockIndent) {
+			break
+		}
+		if blockIndent < 0 {
+			blockIndent = i
+		}
+		lines = append(lines, line[blockIndent:])
+	}
+	trailing := 0
+	for l := len(lines); trailing < l && lines[l-trailing-1] == ""; trailing++ {
+	}
+	lines = lines[:len(lines)-trailing]
+	var b strings.Builder
+	for i, line := range lines {
+		if i != 0 {
+			if folded && line != "" && lines[i-1] != "" {
+				b.WriteByte(' ')
+			} else if !folded || line != "" {
+				b.WriteByte('\n')
+			}
+		}
+		b.WriteString(line)
+	}
+	switch chomping {
+	case "-":
+	case "+":
+		b.WriteString(strings.Repeat("\n", trailing+1))
+	default:
+		if len(lines) != 0 {
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// stripComment removes a comment from the given line.
+func stripComment(text string) string {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' {
+				i++
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || strings.IndexByte(" [{,:", text[i-1]) > -1 {
+				quote = c
+			}
+		case c == '#':
+			if i == 0 || text[i-1] == ' ' {
+				return strings.TrimRight(text[:i], " \t")
+			}
+		}
+	}
+	return strings.TrimRight(text, " \t")
+}
+
+// splitMappingEntry splits a 'key: value' line into a key and a value.
+func splitMappingEntry(text string) (string, string, bool) {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' {
+				i++
+			}
+		case i == 0 && (c == '"' || c == '\''):
+			quote = c
+		case i == 0 && (c == '[' || c == '{'):
+			return "", "", false
+		case c == ':':
+			if i == len(text)-1 || text[i+1] == ' ' {
+				return strings.TrimRight(text[:i], " "), strings.TrimLeft(text[i+1:], " "), i != 0
+			}
+		}
+	}
+	return "", "", false
+}
+
+func isMappingEntry(text string) bool {
+	_, _, ok := splitMappingEntry(text)
+	return ok
+}
+
+// parseScalar parses a single line value.
+func parseScalar(text string) (interface{}, error) {
+	if len(text) != 0 && (text[0] == '[' || text[0] == '{' || text[0] == '"' || text[0] == '\'') {
+		s := &flowScanner{text: text}
+		v, err := s.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		s.skipSpaces()
+		if s.pos != len(s.text) {
+			return nil, fmt.Errorf("unexpected characters after %q", text[:s.pos])
+		}
+		return v, nil
+	}
+	return resolvePlain(text), nil
+}
+
+// resolvePlain converts a plain scalar into a value of suitable type.
+func resolvePlain(text string) interface{} {
+	switch text {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+	if i, err := strconv.ParseInt(text, 10, 0); err == nil {
+		return int(i)
+	}
+	if strings.HasPrefix(text, "0x") {
+		if i, err := strconv.ParseInt(text[2:], 16, 0); err == nil {
+			return int(i)
+		}
+	}
+	if strings.HasPrefix(text, "0o") {
+		if i, err := strconv.ParseInt(text[2:], 8, 0); err == nil {
+			return int(i)
+		}
+	}
+	if strings.IndexAny(text, "0123456789") > -1 {
+		if f, err := strconv.ParseFloat(text, 64); err == nil {
+			return f
+		}
+	}
+	return text
+}
+
+// flowScanner parses flow collections and quoted scalars.
+type flowScanner struct {
+	text string
+	pos  int
+}
+
+func (s *flowScanner) skipSpaces() {
+	for s.pos < len(s.text) && s.text[s.pos] == ' ' {
+		s.pos++
+	}
+}
+
+func (s *flowScanner) parseValue() (interface{}, error) {
+	s.skipSpaces()
+	if s.pos >= len(s.text) {
+		return nil, fmt.Errorf("unexpected end of a flow collection")
+	}
+	switch s.text[s.pos] {
+	case '[':
+		return s.parseSequence()
+	case '{':
+		return s.parseMapping()
+	case '"', '\'':
+		return s.parseQuoted()
+	}
+	start := s.pos
+	for s.pos < len(s.text) {
+		c := s.text[s.pos]
+		if c == ',' || c == ']' || c == '}' {
+			break
+		}
+		if c == ':' && (s.pos+1 == len(s.text) || s.text[s.pos+1] == ' ') {
+			break
+		}
+		s.pos++
+	}
+	return resolvePlain(strings.TrimRight(s.text[start:s.pos], " ")), nil
+}
+
+func (s *flowScanner) parseSequence() (interface{}, error) {
+	s.pos++
+	list := []interface{}{}
+	for {
+		s.skipSpaces()
+		if s.pos < len(s.text) && s.text[s.pos] == ']' {
+			s.pos++
+			return list, nil
+		}
+		v, err := s.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+		if err := s.parseSeparator(']'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (s *flowScanner) parseMapping() (interface{}, error) {
+	s.pos++
+	m := map[string]interface{}{}
+	for {
+		s.skipSpaces()
+		if s.pos < len(s.text) && s.text[s.pos] == '}' {
+			s.pos++
+			return m, nil
+		}
+		key, err := s.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		s.skipSpaces()
+		var value interface{}
+		if s.pos < len(s.text) && s.text[s.pos] == ':' {
+			s.pos++
+			s.skipSpaces()
+			if s.pos < len(s.text) && s.text[s.pos] != ',' && s.text[s.pos] != '}' {
+				if value, err = s.parseValue(); err != nil {
+					return nil, err
+				}
+			}
+		}
+		m[fmt.Sprint(key)] = value
+		if err := s.parseSeparator('}'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseSeparator consumes a ',' or leaves the closer for the caller.
+func (s *flowScanner) parseSeparator(closer byte) error {
+	s.skipSpaces()
+	if s.pos >= len(s.text) {
+		return fmt.Errorf("unexpected end of a flow collection")
+	}
+	switch s.text[s.pos] {
+	case ',':
+		s.pos++
+		return nil
+	case closer:
+		return nil
+	}
+	return fmt.Errorf("unexpected character %q in a flow collection", s.text[s.pos])
+}
+
+func (s *flowScanner) parseQuoted() (interface{}, error) {
+	quote := s.text[s.pos]
+	s.pos++
+	var b strings.Builder
+	for s.pos < len(s.text) {
+		c := s.text[s.pos]
+		s.pos++
+		switch {
+		case c == quote:
+			if quote == '\'' && s.pos < len(s.text) && s.text[s.pos] == '\'' {
+				// '' is an escaped single quote.
+				b.WriteByte('\'')
+				s.pos++
+				continue
+			}
+			return b.String(), nil
+		case c == '\\' && quote == '"' && s.pos < len(s.text):
+			e := s.text[s.pos]
+			s.pos++
+			switch e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case '0':
+				b.WriteByte(0)
+			case 'u':
+				if s.pos+4 > len(s.text) {
+					return nil, fmt.Errorf("invalid escape sequence")
+				}
+				r, err := strconv.ParseUint(s.text[s.pos:s.pos+4], 16, 32)
+				if err != nil {
+					return nil, fmt.Errorf("invalid escape sequence")
+				}
+				b.WriteRune(rune(r))
+				s.pos += 4
+			default:
+				b.WriteByte(e)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return nil, fmt.Errorf("unterminated quoted string")
+}
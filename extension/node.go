@@ -0,0 +1,67 @@
+package extension
+
+import (
+	"fmt"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+type nodeRenderer struct {
+	kind gast.NodeKind
+	f    renderer.NodeRendererFunc
+}
+
+func (r *nodeRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(r.kind, r.f)
+}
+
+type nodeExtension struct {
+	kind     gast.NodeKind
+	parser   interface{}
+	renderer renderer.NodeRendererFunc
+	priority int
+}
+
+// NewNodeExtension returns a new extension that bundles a parser that creates
+// nodes of the given kind and a renderer that renders them.
+//
+// p must be one of parser.BlockParser, parser.InlineParser,
+// parser.ParagraphTransformer and parser.ASTTransformer, or nil if nodes are
+// created elsewhere. The given priority is used for both the parser and the
+// renderer.
+func NewNodeExtension(kind gast.NodeKind, p interface{}, r renderer.NodeRendererFunc, priority int) goldmark.Extender {
+	switch p.(type) {
+	case nil, parser.BlockParser, parser.InlineParser, parser.ParagraphTransformer, parser.ASTTransformer:
+	default:
+		panic(fmt.Sprintf("%T is not a parser nor a transformer", p))
+	}
+	return &nodeExtension{
+		kind:     kind,
+		parser:   p,
+		renderer: r,
+		priority: priority,
+	}
+}
+
+func (e *nodeExtension) Extend(m goldmark.Markdown) {
+	v := util.Prioritized(e.parser, e.priority)
+	switch e.parser.(type) {
+	case parser.BlockParser:
+		m.Parser().AddOptions(parser.WithBlockParsers(v))
+	case parser.InlineParser:
+		m.Parser().AddOptions(parser.WithInlineParsers(v))
+	case parser.ParagraphTransformer:
+		m.Parser().AddOptions(parser.WithParagraphTransformers(v))
+	case parser.ASTTransformer:
+		m.Parser().AddOptions(parser.WithASTTransformers(v))
+	}
+	if e.renderer != nil {
+		m.Renderer().AddOptions(renderer.WithNodeRenderers(
+			util.Prioritized(&nodeRenderer{e.kind, e.renderer}, e.priority),
+		))
+	}
+}
@@ -0,0 +1,59 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/testutil"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+type signature struct {
+	gast.BaseBlock
+}
+
+func (n *signature) Dump(source []byte, level int) {
+	gast.DumpHelper(n, source, level, nil, nil)
+}
+
+var kindSignature = gast.NewNodeKind("Signature")
+
+func (n *signature) Kind() gast.NodeKind {
+	return kindSignature
+}
+
+type signatureTransformer struct {
+}
+
+func (a *signatureTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	node.AppendChild(node, &signature{})
+}
+
+func renderSignature(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		_, _ = w.WriteString("<footer>signature</footer>\n")
+	}
+	return gast.WalkContinue, nil
+}
+
+func TestNodeExtension(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewNodeExtension(kindSignature, &signatureTransformer{}, renderSignature, 500),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "custom nodes inserted by a transformer are rendered",
+			Markdown:    "text",
+			Expected: `<p>text</p>
+<footer>signature</footer>`,
+		},
+		t,
+	)
+}
@@ -0,0 +1,172 @@
+package extension
+
+import (
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// scriptParser parses texts enclosed by single delimiters like '~2~' and
+// '^2^'. As in Pandoc, the enclosed text must be on a single line and must
+// not contain spaces.
+type scriptParser struct {
+	delimiter byte
+	newNode   func() gast.Node
+}
+
+var defaultSubscriptParser = &scriptParser{
+	delimiter: '~',
+	newNode: func() gast.Node {
+		return ast.NewSubscript()
+	},
+}
+
+var defaultSuperscriptParser = &scriptParser{
+	delimiter: '^',
+	newNode: func() gast.Node {
+		return ast.NewSuperscript()
+	},
+}
+
+// NewSubscriptParser return a new InlineParser that parses
+// subscript expressions like 'H~2~O'.
+// Double tildes are left to the strikethrough parser.
+func NewSubscriptParser() parser.InlineParser {
+	return defaultSubscriptParser
+}
+
+// NewSuperscriptParser return a new InlineParser that parses
+// superscript expressions like 'E=mc^2^'.
+func NewSuperscriptParser() parser.InlineParser {
+	return defaultSuperscriptParser
+}
+
+func (s *scriptParser) Trigger() []byte {
+	return []byte{s.delimiter}
+}
+
+func (s *scriptParser) Parse(parent gast.Node, block text.Reader, pc parser.Context) gast.Node {
+	if block.PrecendingCharacter() == rune(s.delimiter) {
+		return nil
+	}
+	line, segment := block.PeekLine()
+	if len(line) < 3 || line[1] == s.delimiter {
+		return nil
+	}
+	i := 1
+	for ; i < len(line); i++ {
+		c := line[i]
+		if c == '\\' && i+1 < len(line) && util.IsPunct(line[i+1]) {
+			i++
+			continue
+		}
+		if c == s.delimiter {
+			break
+		}
+		if util.IsSpace(c) {
+			return nil
+		}
+	}
+	if i == len(line) {
+		return nil
+	}
+	node := s.newNode()
+	node.AppendChild(node, gast.NewTextSegment(text.NewSegment(segment.Start+1, segment.Start+i)))
+	block.Advance(i + 1)
+	return node
+}
+
+func (s *scriptParser) CloseBlock(parent gast.Node, pc parser.Context) {
+	// nothing to do
+}
+
+// ScriptHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders Subscript and Superscript nodes.
+type ScriptHTMLRenderer struct {
+	html.Config
+	kind gast.NodeKind
+	tag  string
+}
+
+// NewSubscriptHTMLRenderer returns a new ScriptHTMLRenderer that renders
+// Subscript nodes as '<sub>'.
+func NewSubscriptHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	return newScriptHTMLRenderer(ast.KindSubscript, "sub", opts...)
+}
+
+// NewSuperscriptHTMLRenderer returns a new ScriptHTMLRenderer that renders
+// Superscript nodes as '<sup>'.
+func NewSuperscriptHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
+	return newScriptHTMLRenderer(ast.KindSuperscript, "sup", opts...)
+}
+
+func newScriptHTMLRenderer(kind gast.NodeKind, tag string, opts ...html.Option) renderer.NodeRenderer {
+	r := &ScriptHTMLRenderer{
+		Config: html.NewConfig(),
+		kind:   kind,
+		tag:    tag,
+	}
+	for _, opt := range opts {
+		opt.SetHTMLOption(&r.Config)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *ScriptHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(r.kind, r.renderScript)
+}
+
+// ScriptAttributeFilter defines attribute names which sub and sup elements can have.
+var ScriptAttributeFilter = html.GlobalAttributeFilter
+
+func (r *ScriptHTMLRenderer) renderScript(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		_ = w.WriteByte('<')
+		_, _ = w.WriteString(r.tag)
+		if n.Attributes() != nil {
+			html.RenderAttributes(w, n, ScriptAttributeFilter)
+		}
+		_ = w.WriteByte('>')
+	} else {
+		_, _ = w.WriteString("</")
+		_, _ = w.WriteString(r.tag)
+		_ = w.WriteByte('>')
+	}
+	return gast.WalkContinue, nil
+}
+
+type subscript struct {
+}
+
+// Subscript is an extension that allow you to use subscript expression like 'H~2~O' .
+var Subscript = &subscript{}
+
+func (e *subscript) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithInlineParsers(
+		util.Prioritized(NewSubscriptParser(), 500),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewSubscriptHTMLRenderer(), 500),
+	))
+}
+
+type superscript struct {
+}
+
+// Superscript is an extension that allow you to use superscript expression like 'E=mc^2^' .
+var Superscript = &superscript{}
+
+func (e *superscript) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithInlineParsers(
+		util.Prioritized(NewSuperscriptParser(), 500),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewSuperscriptHTMLRenderer(), 500),
+	))
+}
@@ -0,0 +1,19 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestScript(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Subscript,
+			Superscript,
+			Strikethrough,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/script.txt", t, testutil.ParseCliCaseArg()...)
+}
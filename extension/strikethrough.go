@@ -60,16 +60,72 @@ func (s *strikethroughParser) CloseBlock(parent gast.Node, pc parser.Context) {
 	// nothing to do
 }
 
+// StrikethroughConfig struct holds options for the extension.
+type StrikethroughConfig struct {
+	html.Config
+
+	// Tag is a name of an element that strikethrough texts are rendered as.
+	// Invalid names are ignored and strikethrough texts are rendered as
+	// '<del>'.
+	Tag string
+}
+
+// StrikethroughOption interface is a functional option interface for the extension.
+type StrikethroughOption interface {
+	renderer.Option
+	// SetStrikethroughOption sets given option to the extension.
+	SetStrikethroughOption(*StrikethroughConfig)
+}
+
+// NewStrikethroughConfig returns a new Config with defaults.
+func NewStrikethroughConfig() StrikethroughConfig {
+	return StrikethroughConfig{
+		Config: html.NewConfig(),
+		Tag:    "del",
+	}
+}
+
+// SetOption implements renderer.SetOptioner.
+func (c *StrikethroughConfig) SetOption(name renderer.OptionName, value interface{}) {
+	switch name {
+	case optStrikethroughTag:
+		c.Tag = value.(string)
+	default:
+		c.Config.SetOption(name, value)
+	}
+}
+
+const optStrikethroughTag renderer.OptionName = "StrikethroughTag"
+
+type withStrikethroughTag struct {
+	value string
+}
+
+func (o *withStrikethroughTag) SetConfig(c *renderer.Config) {
+	c.Options[optStrikethroughTag] = o.value
+}
+
+func (o *withStrikethroughTag) SetStrikethroughOption(c *StrikethroughConfig) {
+	c.Tag = o.value
+}
+
+// WithStrikethroughTag is a functional option that renders strikethrough
+// texts as the given element like '<s>' instead of '<del>'.
+// The tag must be a valid element name, otherwise '<del>' is used.
+func WithStrikethroughTag(tag string) StrikethroughOption {
+	return &withStrikethroughTag{tag}
+}
+
 // StrikethroughHTMLRenderer is a renderer.NodeRenderer implementation that
 // renders Strikethrough nodes.
 type StrikethroughHTMLRenderer struct {
-	html.Config
+	StrikethroughConfig
 }
 
 // NewStrikethroughHTMLRenderer returns a new StrikethroughHTMLRenderer.
 func NewStrikethroughHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
 	r := &StrikethroughHTMLRenderer{
-		Config: html.NewConfig(),
+		StrikethroughConfig: NewStrikethroughConfig(),
 	}
 	for _, opt := range opts {
 		opt.SetHTMLOption(&r.Config)
@@ -86,31 +142,50 @@ func (r *StrikethroughHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncR
 var StrikethroughAttributeFilter = html.GlobalAttributeFilter
 
 func (r *StrikethroughHTMLRenderer) renderStrikethrough(w util.BufWriter, source []byte, n gast.Node, entering bool) (gast.WalkStatus, error) {
+	tag := r.Tag
+	if !htmlTagNameRegexp.MatchString(tag) {
+		tag = "del"
+	}
 	if entering {
+		_ = w.WriteByte('<')
+		_, _ = w.WriteString(tag)
 		if n.Attributes() != nil {
-			_, _ = w.WriteString("<del")
 			html.RenderAttributes(w, n, StrikethroughAttributeFilter)
-			_ = w.WriteByte('>')
-		} else {
-			_, _ = w.WriteString("<del>")
 		}
+		_ = w.WriteByte('>')
 	} else {
-		_, _ = w.WriteString("</del>")
+		_, _ = w.WriteString("</")
+		_, _ = w.WriteString(tag)
+		_ = w.WriteByte('>')
 	}
 	return gast.WalkContinue, nil
 }
 
 type strikethrough struct {
+	options []StrikethroughOption
 }
 
 // Strikethrough is an extension that allow you to use strikethrough expression like '~~text~~' .
 var Strikethrough = &strikethrough{}
 
+// NewStrikethrough returns a new extension with given options.
+func NewStrikethrough(opts ...StrikethroughOption) goldmark.Extender {
+	return &strikethrough{
+		options: opts,
+	}
+}
+
 func (e *strikethrough) Extend(m goldmark.Markdown) {
+	r := &StrikethroughHTMLRenderer{
+		StrikethroughConfig: NewStrikethroughConfig(),
+	}
+	for _, opt := range e.options {
+		opt.SetStrikethroughOption(&r.StrikethroughConfig)
+	}
 	m.Parser().AddOptions(parser.WithInlineParsers(
 		util.Prioritized(NewStrikethroughParser(), 500),
 	))
 	m.Renderer().AddOptions(renderer.WithNodeRenderers(
-		util.Prioritized(NewStrikethroughHTMLRenderer(), 500),
+		util.Prioritized(r, 500),
 	))
 }
@@ -19,3 +19,62 @@ func TestStrikethrough(t *testing.T) {
 	)
 	testutil.DoTestCaseFile(markdown, "_test/strikethrough.txt", t, testutil.ParseCliCaseArg()...)
 }
+
+func TestStrikethroughTag(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewStrikethrough(
+				WithStrikethroughTag("s"),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Strikethrough texts are rendered as the given tag",
+			Markdown:    "~~Hi~~ Hello, world!",
+			Expected:    "<p><s>Hi</s> Hello, world!</p>",
+		},
+		t,
+	)
+
+	markdown = goldmark.New(
+		goldmark.WithExtensions(
+			Strikethrough,
+		),
+		goldmark.WithRendererOptions(
+			WithStrikethroughTag("s"),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "WithStrikethroughTag can be used as a renderer option",
+			Markdown:    "~~Hi~~ Hello, world!",
+			Expected:    "<p><s>Hi</s> Hello, world!</p>",
+		},
+		t,
+	)
+
+	for i, tag := range []string{"s onclick=x", ""} {
+		markdown = goldmark.New(
+			goldmark.WithExtensions(
+				NewStrikethrough(
+					WithStrikethroughTag(tag),
+				),
+			),
+		)
+		testutil.DoTestCase(
+			markdown,
+			testutil.MarkdownTestCase{
+				No:          3 + i,
+				Description: "Invalid tags are ignored",
+				Markdown:    "~~Hi~~ Hello, world!",
+				Expected:    "<p><del>Hi</del> Hello, world!</p>",
+			},
+			t,
+		)
+	}
+}
@@ -50,6 +50,29 @@ type TableConfig struct {
 
 	// TableCellAlignMethod indicates how are table celss aligned.
 	TableCellAlignMethod TableCellAlignMethod
+
+	// CellLineBreakSeparator is a separator that is converted into a line
+	// break(<br>) in table cells.
+	CellLineBreakSeparator []byte
+
+	// Scopes indicates that header cells should have scope attributes.
+	Scopes bool
+
+	// RowHeaders indicates that the first cell of each body row should be
+	// rendered as a header cell.
+	RowHeaders bool
+
+	// HeaderlessTables indicates that blocks of rows that start and end with
+	// '|' should be parsed as tables even if they do not have delimiter rows.
+	HeaderlessTables bool
+
+	// ColGroup indicates that tables should have colgroup elements that
+	// have col elements with alignments of columns.
+	ColGroup bool
+
+	// CellSpans indicates that cells containing only '>' or '^' should be
+	// merged with the previous cell or the cell above.
+	CellSpans bool
 }
 
 // TableOption interface is a functional option interface for the extension.
@@ -72,6 +95,18 @@ func (c *TableConfig) SetOption(name renderer.OptionName, value interface{}) {
 	switch name {
 	case optTableCellAlignMethod:
 		c.TableCellAlignMethod = value.(TableCellAlignMethod)
+	case optTableCellLineBreakSeparator:
+		c.CellLineBreakSeparator = value.([]byte)
+	case optTableScopes:
+		c.Scopes = value.(bool)
+	case optTableRowHeaders:
+		c.RowHeaders = value.(bool)
+	case optTableCellSpans:
+		c.CellSpans = value.(bool)
+	case optHeaderlessTables:
+		c.HeaderlessTables = value.(bool)
+	case optTableColGroup:
+		c.ColGroup = value.(bool)
 	default:
 		c.Config.SetOption(name, value)
 	}
@@ -121,6 +156,124 @@ func WithTableCellAlignMethod(a TableCellAlignMethod) TableOption {
 	return &withTableCellAlignMethod{a}
 }
 
+const optTableCellLineBreakSeparator renderer.OptionName = "TableCellLineBreakSeparator"
+
+type withTableCellLineBreakSeparator struct {
+	value []byte
+}
+
+func (o *withTableCellLineBreakSeparator) SetConfig(c *renderer.Config) {
+	c.Options[optTableCellLineBreakSeparator] = o.value
+}
+
+func (o *withTableCellLineBreakSeparator) SetTableOption(c *TableConfig) {
+	c.CellLineBreakSeparator = o.value
+}
+
+// WithTableCellLineBreakSeparator is a functional option that converts
+// the given separator in table cells into a line break.
+// With `\n`, a cell like `a\nb` is rendered as `a<br>b`.
+func WithTableCellLineBreakSeparator(separator string) TableOption {
+	return &withTableCellLineBreakSeparator{[]byte(separator)}
+}
+
+const optTableScopes renderer.OptionName = "TableScopes"
+
+type withTableScopes struct {
+}
+
+func (o *withTableScopes) SetConfig(c *renderer.Config) {
+	c.Options[optTableScopes] = true
+}
+
+func (o *withTableScopes) SetTableOption(c *TableConfig) {
+	c.Scopes = true
+}
+
+// WithTableScopes is a functional option that renders scope="col" on
+// <th> cells in the header row.
+func WithTableScopes() TableOption {
+	return &withTableScopes{}
+}
+
+const optTableRowHeaders renderer.OptionName = "TableRowHeaders"
+
+type withTableRowHeaders struct {
+}
+
+func (o *withTableRowHeaders) SetConfig(c *renderer.Config) {
+	c.Options[optTableRowHeaders] = true
+}
+
+func (o *withTableRowHeaders) SetTableOption(c *TableConfig) {
+	c.RowHeaders = true
+}
+
+// WithTableRowHeaders is a functional option that renders the first cell of
+// each body row as <th scope="row">.
+func WithTableRowHeaders() TableOption {
+	return &withTableRowHeaders{}
+}
+
+const optTableCellSpans renderer.OptionName = "TableCellSpans"
+
+type withTableCellSpans struct {
+}
+
+func (o *withTableCellSpans) SetConfig(c *renderer.Config) {
+	c.Options[optTableCellSpans] = true
+}
+
+func (o *withTableCellSpans) SetTableOption(c *TableConfig) {
+	c.CellSpans = true
+}
+
+// WithTableCellSpans is a functional option that merges a cell containing
+// only '>' into the previous cell(colspan) and a cell containing only '^'
+// into the cell above(rowspan).
+func WithTableCellSpans() TableOption {
+	return &withTableCellSpans{}
+}
+
+const optHeaderlessTables renderer.OptionName = "HeaderlessTables"
+
+type withHeaderlessTables struct {
+}
+
+func (o *withHeaderlessTables) SetConfig(c *renderer.Config) {
+	c.Options[optHeaderlessTables] = true
+}
+
+func (o *withHeaderlessTables) SetTableOption(c *TableConfig) {
+	c.HeaderlessTables = true
+}
+
+// WithHeaderlessTables is a functional option that parses blocks of rows
+// without delimiter rows as tables that have only bodies.
+// Each row must start and end with '|'.
+func WithHeaderlessTables() TableOption {
+	return &withHeaderlessTables{}
+}
+
+const optTableColGroup renderer.OptionName = "TableColGroup"
+
+type withTableColGroup struct {
+}
+
+func (o *withTableColGroup) SetConfig(c *renderer.Config) {
+	c.Options[optTableColGroup] = true
+}
+
+func (o *withTableColGroup) SetTableOption(c *TableConfig) {
+	c.ColGroup = true
+}
+
+// WithTableColGroup is a functional option that renders a <colgroup> that
+// has a <col> with an alignment for each column.
+func WithTableColGroup() TableOption {
+	return &withTableColGroup{}
+}
+
 func isTableDelim(bs []byte) bool {
 	if w, _ := util.IndentWidth(bs, 0); w > 3 {
 		return false
@@ -139,17 +292,32 @@ var tableDelimCenter = regexp.MustCompile(`^\s*\:\-+\:\s*$`)
 var tableDelimNone = regexp.MustCompile(`^\s*\-+\s*$`)
 
 type tableParagraphTransformer struct {
+	headerless bool
 }
 
 var defaultTableParagraphTransformer = &tableParagraphTransformer{}
 
+var defaultHeaderlessTableParagraphTransformer = &tableParagraphTransformer{
+	headerless: true,
+}
+
 // NewTableParagraphTransformer returns  a new ParagraphTransformer
 // that can transform paragraphs into tables.
 func NewTableParagraphTransformer() parser.ParagraphTransformer {
 	return defaultTableParagraphTransformer
 }
 
+// NewHeaderlessTableParagraphTransformer returns a new ParagraphTransformer
+// that can transform paragraphs into tables. Paragraphs that consist of rows
+// starting and ending with '|' are transformed into tables without headers.
+func NewHeaderlessTableParagraphTransformer() parser.ParagraphTransformer {
+	return defaultHeaderlessTableParagraphTransformer
+}
+
 func (b *tableParagraphTransformer) Transform(node *gast.Paragraph, reader text.Reader, pc parser.Context) {
+	if b.headerless && b.transformHeaderless(node, reader, pc) {
+		return
+	}
 	lines := node.Lines()
 	if lines.Len() < 2 {
 		return
@@ -181,6 +349,35 @@ func (b *tableParagraphTransformer) Transform(node *gast.Paragraph, reader text.
 	}
 }
 
+func (b *tableParagraphTransformer) transformHeaderless(node *gast.Paragraph, reader text.Reader, pc parser.Context) bool {
+	lines := node.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		line := util.TrimRightSpace(util.TrimLeftSpace(segment.Value(reader.Source())))
+		if len(line) < 2 || line[0] != '|' || line[len(line)-1] != '|' || line[len(line)-2] == '\\' {
+			return false
+		}
+		if i != 0 && b.parseDelimiter(lines.At(i), reader) != nil {
+			return false
+		}
+	}
+	first := b.parseRow(lines.At(0), nil, true, reader, pc)
+	alignments := make([]ast.Alignment, first.ChildCount())
+	for i := range alignments {
+		alignments[i] = ast.AlignNone
+	}
+	first.Alignments = alignments
+	table := ast.NewTable()
+	table.Alignments = alignments
+	table.AppendChild(table, first)
+	for i := 1; i < lines.Len(); i++ {
+		table.AppendChild(table, b.parseRow(lines.At(i), alignments, false, reader, pc))
+	}
+	node.Parent().InsertAfter(node.Parent(), node, table)
+	node.Parent().RemoveChild(node.Parent(), node)
+	return true
+}
+
 func (b *tableParagraphTransformer) parseRow(segment text.Segment, alignments []ast.Alignment, isHeader bool, reader text.Reader, pc parser.Context) *ast.TableRow {
 	source := reader.Source()
 	line := segment.Value(source)
@@ -331,6 +528,183 @@ func (a *tableASTTransformer) Transform(node *gast.Document, reader text.Reader,
 	}
 }
 
+type tableCellLineBreakASTTransformer struct {
+	separator []byte
+}
+
+// NewTableCellLineBreakASTTransformer returns a parser.ASTTransformer that
+// converts the given separator in table cells into hard line breaks.
+func NewTableCellLineBreakASTTransformer(separator []byte) parser.ASTTransformer {
+	return &tableCellLineBreakASTTransformer{separator}
+}
+
+func (a *tableCellLineBreakASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	if len(a.separator) == 0 {
+		return
+	}
+	source := reader.Source()
+	var texts []*gast.Text
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		switch n.Kind() {
+		case gast.KindCodeSpan:
+			return gast.WalkSkipChildren, nil
+		case gast.KindText:
+			if t := n.(*gast.Text); !t.IsRaw() && isInTableCell(n) {
+				texts = append(texts, t)
+			}
+		}
+		return gast.WalkContinue, nil
+	})
+	for _, t := range texts {
+		parent := t.Parent()
+		current := t
+		for {
+			segment := current.Segment
+			i := bytes.Index(segment.Value(source), a.separator)
+			if i < 0 {
+				break
+			}
+			n1 := gast.NewTextSegment(segment.WithStop(segment.Start + i))
+			n1.SetHardLineBreak(true)
+			n2 := gast.NewTextSegment(segment.WithStart(segment.Start + i + len(a.separator)))
+			n2.SetSoftLineBreak(current.SoftLineBreak())
+			n2.SetHardLineBreak(current.HardLineBreak())
+			parent.InsertBefore(parent, current, n1)
+			parent.ReplaceChild(parent, current, n2)
+			current = n2
+		}
+	}
+}
+
+func isInTableCell(n gast.Node) bool {
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		if p.Kind() == ast.KindTableCell {
+			return true
+		}
+	}
+	return false
+}
+
+type tableRowHeaderASTTransformer struct {
+}
+
+var defaultTableRowHeaderASTTransformer = &tableRowHeaderASTTransformer{}
+
+// NewTableRowHeaderASTTransformer returns a parser.ASTTransformer that
+// marks the first cell of each body row as a row header cell. Rows whose
+// first columns are covered by row header cells spanning rows are skipped.
+func NewTableRowHeaderASTTransformer() parser.ASTTransformer {
+	return defaultTableRowHeaderASTTransformer
+}
+
+func (a *tableRowHeaderASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering || n.Kind() != ast.KindTable {
+			return gast.WalkContinue, nil
+		}
+		// covered is a number of rows whose first column is covered by
+		// a row header cell spanning rows.
+		covered := 0
+		for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+			if row.Kind() != ast.KindTableRow {
+				continue
+			}
+			if covered > 0 {
+				covered--
+				continue
+			}
+			if cell, ok := row.FirstChild().(*ast.TableCell); ok {
+				cell.IsRowHeader = true
+				covered = cell.RowSpan - 1
+			}
+		}
+		return gast.WalkSkipChildren, nil
+	})
+}
+
+type tableCellSpanASTTransformer struct {
+}
+
+var defaultTableCellSpanASTTransformer = &tableCellSpanASTTransformer{}
+
+// NewTableCellSpanASTTransformer returns a parser.ASTTransformer that
+// merges a cell containing only '>' into the previous cell and a cell
+// containing only '^' into the cell above.
+func NewTableCellSpanASTTransformer() parser.ASTTransformer {
+	return defaultTableCellSpanASTTransformer
+}
+
+func (a *tableCellSpanASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering || n.Kind() != ast.KindTable {
+			return gast.WalkContinue, nil
+		}
+		// above holds cells that cover each column of the previous row.
+		var above []*ast.TableCell
+		for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+			// rowspans can not cross the boundary between a header and a body.
+			if row.PreviousSibling() != nil && row.PreviousSibling().Kind() == ast.KindTableHeader {
+				above = nil
+			}
+			above = mergeTableCells(row, above, source)
+		}
+		return gast.WalkSkipChildren, nil
+	})
+}
+
+func mergeTableCells(row gast.Node, above []*ast.TableCell, source []byte) []*ast.TableCell {
+	var current []*ast.TableCell
+	var starts []int
+	for c := row.FirstChild(); c != nil; {
+		next := c.NextSibling()
+		cell := c.(*ast.TableCell)
+		col := len(current)
+		switch tableCellSpanMarker(cell, source) {
+		case '>':
+			if col > 0 && starts[col-1] >= 0 {
+				prev := current[col-1]
+				prev.ColSpan++
+				row.RemoveChild(row, cell)
+				current = append(current, prev)
+				starts = append(starts, starts[col-1])
+				c = next
+				continue
+			}
+		case '^':
+			if col < len(above) && above[col] != nil && above[col].ColSpan == 1 {
+				up := above[col]
+				up.RowSpan++
+				row.RemoveChild(row, cell)
+				current = append(current, up)
+				// cells spanning rows can not be extended to the right.
+				starts = append(starts, -1)
+				c = next
+				continue
+			}
+		}
+		current = append(current, cell)
+		starts = append(starts, col)
+		c = next
+	}
+	return current
+}
+
+func tableCellSpanMarker(cell *ast.TableCell, source []byte) byte {
+	if cell.Lines().Len() != 1 {
+		return 0
+	}
+	segment := cell.Lines().At(0)
+	value := segment.Value(source)
+	if len(value) != 1 {
+		return 0
+	}
+	return value[0]
+}
+
 // TableHTMLRenderer is a renderer.NodeRenderer implementation that
 // renders Table nodes.
 type TableHTMLRenderer struct {
@@ -376,12 +750,51 @@ func (r *TableHTMLRenderer) renderTable(w util.BufWriter, source []byte, n gast.
 			html.RenderAttributes(w, n, TableAttributeFilter)
 		}
 		_, _ = w.WriteString(">\n")
+		if r.TableConfig.ColGroup {
+			r.renderColGroup(w, n.(*ast.Table))
+		}
+		if n.FirstChild() != nil && n.FirstChild().Kind() == ast.KindTableRow {
+			_, _ = w.WriteString("<tbody>\n")
+		}
 	} else {
 		_, _ = w.WriteString("</table>\n")
 	}
 	return gast.WalkContinue, nil
 }
 
+func (r *TableHTMLRenderer) renderColGroup(w util.BufWriter, n *ast.Table) {
+	_, _ = w.WriteString("<colgroup>\n")
+	for _, alignment := range n.Alignments {
+		_, _ = w.WriteString("<col")
+		if alignment != ast.AlignNone {
+			switch r.alignMethod() {
+			case TableCellAlignAttribute:
+				fmt.Fprintf(w, ` align="%s"`, alignment.String())
+			case TableCellAlignStyle:
+				fmt.Fprintf(w, ` style="text-align:%s"`, alignment.String())
+			}
+		}
+		if r.Config.XHTML {
+			_, _ = w.WriteString(" />\n")
+		} else {
+			_, _ = w.WriteString(">\n")
+		}
+	}
+	_, _ = w.WriteString("</colgroup>\n")
+}
+
+func (r *TableHTMLRenderer) alignMethod() TableCellAlignMethod {
+	amethod := r.TableConfig.TableCellAlignMethod
+	if amethod == TableCellAlignDefault {
+		if r.Config.XHTML {
+			amethod = TableCellAlignAttribute
+		} else {
+			amethod = TableCellAlignStyle
+		}
+	}
+	return amethod
+}
+
 // TableHeaderAttributeFilter defines attribute names which <thead> elements can have.
 var TableHeaderAttributeFilter = html.GlobalAttributeFilter.Extend(
 	[]byte("align"),   // [Deprecated since HTML4] [Obsolete since HTML5]
@@ -480,43 +893,43 @@ var TableTdCellAttributeFilter = html.GlobalAttributeFilter.Extend(
 func (r *TableHTMLRenderer) renderTableCell(w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
 	n := node.(*ast.TableCell)
 	tag := "td"
-	if n.Parent().Kind() == ast.KindTableHeader {
+	if n.Parent().Kind() == ast.KindTableHeader || n.IsRowHeader {
 		tag = "th"
 	}
 	if entering {
 		fmt.Fprintf(w, "<%s", tag)
-		if n.Alignment != ast.AlignNone {
-			amethod := r.TableConfig.TableCellAlignMethod
-			if amethod == TableCellAlignDefault {
-				if r.Config.XHTML {
-					amethod = TableCellAlignAttribute
-				} else {
-					amethod = TableCellAlignStyle
-				}
+		if _, ok := n.AttributeString("scope"); !ok {
+			if n.IsRowHeader {
+				_, _ = w.WriteString(` scope="row"`)
+			} else if r.TableConfig.Scopes && tag == "th" {
+				_, _ = w.WriteString(` scope="col"`)
 			}
-			switch amethod {
+		}
+		if _, ok := n.AttributeString("colspan"); !ok && n.ColSpan > 1 {
+			fmt.Fprintf(w, ` colspan="%d"`, n.ColSpan)
+		}
+		if _, ok := n.AttributeString("rowspan"); !ok && n.RowSpan > 1 {
+			fmt.Fprintf(w, ` rowspan="%d"`, n.RowSpan)
+		}
+		filter := TableTdCellAttributeFilter // <td>
+		if tag == "th" {
+			filter = TableThCellAttributeFilter // <th>
+		}
+		style := ""
+		if n.Alignment != ast.AlignNone {
+			switch r.alignMethod() {
 			case TableCellAlignAttribute:
 				if _, ok := n.AttributeString("align"); !ok { // Skip align render if overridden
 					fmt.Fprintf(w, ` align="%s"`, n.Alignment.String())
 				}
 			case TableCellAlignStyle:
-				v, ok := n.AttributeString("style")
-				var cob util.CopyOnWriteBuffer
-				if ok {
-					cob = util.NewCopyOnWriteBuffer(v.([]byte))
-					cob.AppendByte(';')
-				}
-				style := fmt.Sprintf("text-align:%s", n.Alignment.String())
-				cob.AppendString(style)
-				n.SetAttributeString("style", cob.Bytes())
+				style = fmt.Sprintf("text-align:%s", n.Alignment.String())
 			}
 		}
-		if n.Attributes() != nil {
-			if tag == "td" {
-				html.RenderAttributes(w, n, TableTdCellAttributeFilter) // <td>
-			} else {
-				html.RenderAttributes(w, n, TableThCellAttributeFilter) // <th>
-			}
+		if len(style) != 0 {
+			html.RenderAttributesWithStyle(w, n, filter, style)
+		} else if n.Attributes() != nil {
+			html.RenderAttributes(w, n, filter)
 		}
 		_ = w.WriteByte('>')
 	} else {
@@ -542,14 +955,43 @@ func NewTable(opts ...TableOption) goldmark.Extender {
 }
 
 func (e *table) Extend(m goldmark.Markdown) {
+	config := NewTableConfig()
+	for _, opt := range e.options {
+		opt.SetTableOption(&config)
+	}
+	paragraphTransformer := NewTableParagraphTransformer()
+	if config.HeaderlessTables {
+		paragraphTransformer = NewHeaderlessTableParagraphTransformer()
+	}
 	m.Parser().AddOptions(
 		parser.WithParagraphTransformers(
-			util.Prioritized(NewTableParagraphTransformer(), 200),
+			util.Prioritized(paragraphTransformer, 200),
 		),
 		parser.WithASTTransformers(
 			util.Prioritized(defaultTableASTTransformer, 0),
 		),
 	)
+	if config.CellSpans {
+		m.Parser().AddOptions(
+			parser.WithASTTransformers(
+				util.Prioritized(NewTableCellSpanASTTransformer(), 50),
+			),
+		)
+	}
+	if config.RowHeaders {
+		m.Parser().AddOptions(
+			parser.WithASTTransformers(
+				util.Prioritized(NewTableRowHeaderASTTransformer(), 100),
+			),
+		)
+	}
+	if len(config.CellLineBreakSeparator) != 0 {
+		m.Parser().AddOptions(
+			parser.WithASTTransformers(
+				util.Prioritized(NewTableCellLineBreakASTTransformer(config.CellLineBreakSeparator), 100),
+			),
+		)
+	}
 	m.Renderer().AddOptions(renderer.WithNodeRenderers(
 		util.Prioritized(NewTableHTMLRenderer(e.options...), 500),
 	))
@@ -1,6 +1,8 @@
 package extension
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"testing"
 
 	"github.com/yuin/goldmark"
@@ -355,3 +357,526 @@ bar | baz
 		t,
 	)
 }
+
+func TestTableCellLineBreak(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+		),
+		goldmark.WithExtensions(
+			Table,
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "<br> in cells should be rendered as a line break",
+			Markdown: `
+| abc |
+| --- |
+| a<br>b |
+`,
+			Expected: `<table>
+<thead>
+<tr>
+<th>abc</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td>a<br>b</td>
+</tr>
+</tbody>
+</table>`,
+		},
+		t,
+	)
+
+	markdown = goldmark.New(
+		goldmark.WithExtensions(
+			NewTable(
+				WithTableCellLineBreakSeparator(`\n`),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "Separators in cells should be converted into line breaks",
+			Markdown: `
+| abc |
+| --- |
+| a\nb |
+| ` + "`c\\nd`" + ` |
+`,
+			Expected: `<table>
+<thead>
+<tr>
+<th>abc</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td>a<br>
+b</td>
+</tr>
+<tr>
+<td><code>c\nd</code></td>
+</tr>
+</tbody>
+</table>`,
+		},
+		t,
+	)
+}
+
+func TestTableScopes(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewTable(
+				WithTableScopes(),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Header cells should have scope attributes",
+			Markdown: `
+| abc | def |
+| :-- | --- |
+| bar | baz |
+`,
+			Expected: `<table>
+<thead>
+<tr>
+<th scope="col" style="text-align:left">abc</th>
+<th scope="col">def</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td style="text-align:left">bar</td>
+<td>baz</td>
+</tr>
+</tbody>
+</table>`,
+		},
+		t,
+	)
+}
+
+func TestTableRowHeaders(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewTable(
+				WithTableScopes(),
+				WithTableRowHeaders(),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "The first cell of each body row should be a header cell",
+			Markdown: `
+| name | value |
+| ---- | ----- |
+| foo  | 1     |
+| bar  | 2     |
+`,
+			Expected: `<table>
+<thead>
+<tr>
+<th scope="col">name</th>
+<th scope="col">value</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<th scope="row">foo</th>
+<td>1</td>
+</tr>
+<tr>
+<th scope="row">bar</th>
+<td>2</td>
+</tr>
+</tbody>
+</table>`,
+		},
+		t,
+	)
+}
+
+func TestTableCellSpans(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewTable(
+				WithTableCellSpans(),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Cells containing only '>' or '^' are merged",
+			Markdown: `
+| a | b | c |
+|:-:|---|--:|
+| 1 | > | 2 |
+| 3 | 4 | ^ |
+| ^ | \> | > |
+`,
+			Expected: `<table>
+<thead>
+<tr>
+<th style="text-align:center">a</th>
+<th>b</th>
+<th style="text-align:right">c</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td colspan="2" style="text-align:center">1</td>
+<td rowspan="2" style="text-align:right">2</td>
+</tr>
+<tr>
+<td rowspan="2" style="text-align:center">3</td>
+<td>4</td>
+</tr>
+<tr>
+<td colspan="2">&gt;</td>
+</tr>
+</tbody>
+</table>`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "Cells that can not be merged are rendered as they are",
+			Markdown: `
+| > | a |
+|---|---|
+| ^ | > |
+| > | ^ |
+`,
+			Expected: `<table>
+<thead>
+<tr>
+<th>&gt;</th>
+<th>a</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td colspan="2">^</td>
+</tr>
+<tr>
+<td>&gt;</td>
+<td>^</td>
+</tr>
+</tbody>
+</table>`,
+		},
+		t,
+	)
+
+	plain := goldmark.New(goldmark.WithExtensions(Table))
+	source := []byte(`
+| a | b |
+|:--|--:|
+| 1 | 2 |
+| 3 |
+`)
+	var expected, actual bytes.Buffer
+	if err := plain.Convert(source, &expected); err != nil {
+		t.Fatal(err)
+	}
+	if err := markdown.Convert(source, &actual); err != nil {
+		t.Fatal(err)
+	}
+	if expected.String() != actual.String() {
+		t.Errorf("tables without merged cells must not be changed:\n%s\n%s", expected.String(), actual.String())
+	}
+}
+
+func TestHeaderlessTables(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewTable(
+				WithHeaderlessTables(),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Rows without a delimiter row are parsed as a table without a header",
+			Markdown: `
+| a | b |
+| c | d | e |
+| f |
+
+| x | y |
+|---|---|
+| 1 | 2 |
+`,
+			Expected: `<table>
+<tbody>
+<tr>
+<td>a</td>
+<td>b</td>
+</tr>
+<tr>
+<td>c</td>
+<td>d</td>
+</tr>
+<tr>
+<td>f</td>
+<td></td>
+</tr>
+</tbody>
+</table>
+<table>
+<thead>
+<tr>
+<th>x</th>
+<th>y</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td>1</td>
+<td>2</td>
+</tr>
+</tbody>
+</table>`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "Rows must start and end with '|'",
+			Markdown: `
+| a | b
+| c | d |
+
+a | b |
+`,
+			Expected: `<p>| a | b
+| c | d |</p>
+<p>a | b |</p>`,
+		},
+		t,
+	)
+
+	testutil.DoTestCase(
+		goldmark.New(goldmark.WithExtensions(Table)),
+		testutil.MarkdownTestCase{
+			No:          3,
+			Description: "Rows without a delimiter row are not a table by default",
+			Markdown: `
+| a | b |
+| c | d |
+`,
+			Expected: `<p>| a | b |
+| c | d |</p>`,
+		},
+		t,
+	)
+}
+
+func TestTableColGroup(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewTable(
+				WithTableColGroup(),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Tables should have colgroups with alignments",
+			Markdown: `
+| a | b | c |
+|:--|---|--:|
+| 1 | 2 | 3 |
+`,
+			Expected: `<table>
+<colgroup>
+<col style="text-align:left">
+<col>
+<col style="text-align:right">
+</colgroup>
+<thead>
+<tr>
+<th style="text-align:left">a</th>
+<th>b</th>
+<th style="text-align:right">c</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td style="text-align:left">1</td>
+<td>2</td>
+<td style="text-align:right">3</td>
+</tr>
+</tbody>
+</table>`,
+		},
+		t,
+	)
+
+	markdown = goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithXHTML(),
+		),
+		goldmark.WithExtensions(
+			NewTable(
+				WithTableColGroup(),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "Cols should be rendered as XHTML with align attributes",
+			Markdown: `
+| a | b |
+|:-:|---|
+`,
+			Expected: `<table>
+<colgroup>
+<col align="center" />
+<col />
+</colgroup>
+<thead>
+<tr>
+<th align="center">a</th>
+<th>b</th>
+</tr>
+</thead>
+</table>`,
+		},
+		t,
+	)
+}
+
+type tableMapCache struct {
+	entries map[[sha256.Size]byte]*parser.CacheEntry
+}
+
+func (c *tableMapCache) Get(hash [sha256.Size]byte) (*parser.CacheEntry, bool) {
+	e, ok := c.entries[hash]
+	return e, ok
+}
+
+func (c *tableMapCache) Put(hash [sha256.Size]byte, entry *parser.CacheEntry) {
+	c.entries[hash] = entry
+}
+
+func TestTableWithCache(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithParserOptions(
+			parser.WithAttribute(),
+			parser.WithCache(&tableMapCache{entries: map[[sha256.Size]byte]*parser.CacheEntry{}}),
+		),
+		goldmark.WithExtensions(
+			NewTable(
+				WithTableCellAlignMethod(TableCellAlignStyle),
+			),
+		),
+	)
+	source := []byte("| a | b |\n|:--|--:|\n| c | d |\n")
+	expected := `<table>
+<thead>
+<tr>
+<th style="text-align:left">a</th>
+<th style="text-align:right">b</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td style="text-align:left">c</td>
+<td style="text-align:right">d</td>
+</tr>
+</tbody>
+</table>
+`
+	for i := 0; i < 3; i++ {
+		var buf bytes.Buffer
+		if err := markdown.Convert(source, &buf); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != expected {
+			t.Errorf("%d: expected:\n%s\nbut got:\n%s", i, expected, buf.String())
+		}
+	}
+}
+
+func TestTableRowHeadersWithCellSpans(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewTable(
+				WithTableRowHeaders(),
+				WithTableCellSpans(),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Rows whose first column is covered by a rowspan have no row headers",
+			Markdown: `
+| h | x | y |
+|---|---|--:|
+| a | b | c |
+| ^ | e | f |
+| g | h | i |
+`,
+			Expected: `<table>
+<thead>
+<tr>
+<th>h</th>
+<th>x</th>
+<th style="text-align:right">y</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<th scope="row" rowspan="2">a</th>
+<td>b</td>
+<td style="text-align:right">c</td>
+</tr>
+<tr>
+<td>e</td>
+<td style="text-align:right">f</td>
+</tr>
+<tr>
+<th scope="row">g</th>
+<td>h</td>
+<td style="text-align:right">i</td>
+</tr>
+</tbody>
+</table>`,
+		},
+		t,
+	)
+}
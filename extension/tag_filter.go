@@ -0,0 +1,213 @@
+package extension
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/util"
+)
+
+var disallowedTagRegexp = regexp.MustCompile(`(?i)</?(?:title|textarea|style|xmp|iframe|noembed|noframes|script|plaintext)(?:[\s/>]|$)`)
+
+// TagFilterConfig struct holds options for the extension.
+type TagFilterConfig struct {
+	html.Config
+
+	// Highlight indicates that disallowed tags should be rendered as escaped
+	// texts wrapped in '<span class="blocked-html">'.
+	Highlight bool
+}
+
+// TagFilterOption interface is a functional option interface for the extension.
+type TagFilterOption interface {
+	renderer.Option
+	// SetTagFilterOption sets given option to the extension.
+	SetTagFilterOption(*TagFilterConfig)
+}
+
+// NewTagFilterConfig returns a new Config with defaults.
+func NewTagFilterConfig() TagFilterConfig {
+	return TagFilterConfig{
+		Config:    html.NewConfig(),
+		Highlight: false,
+	}
+}
+
+// SetOption implements renderer.SetOptioner.
+func (c *TagFilterConfig) SetOption(name renderer.OptionName, value interface{}) {
+	switch name {
+	case optTagFilterHighlight:
+		c.Highlight = value.(bool)
+	default:
+		c.Config.SetOption(name, value)
+	}
+}
+
+type withTagFilterHTMLOptions struct {
+	value []html.Option
+}
+
+func (o *withTagFilterHTMLOptions) SetConfig(c *renderer.Config) {
+	if o.value != nil {
+		for _, v := range o.value {
+			v.(renderer.Option).SetConfig(c)
+		}
+	}
+}
+
+func (o *withTagFilterHTMLOptions) SetTagFilterOption(c *TagFilterConfig) {
+	if o.value != nil {
+		for _, v := range o.value {
+			v.SetHTMLOption(&c.Config)
+		}
+	}
+}
+
+// WithTagFilterHTMLOptions is functional option that wraps goldmark HTMLRenderer options.
+func WithTagFilterHTMLOptions(opts ...html.Option) TagFilterOption {
+	return &withTagFilterHTMLOptions{opts}
+}
+
+const optTagFilterHighlight renderer.OptionName = "TagFilterHighlight"
+
+type withTagFilterHighlight struct {
+}
+
+func (o *withTagFilterHighlight) SetConfig(c *renderer.Config) {
+	c.Options[optTagFilterHighlight] = true
+}
+
+func (o *withTagFilterHighlight) SetTagFilterOption(c *TagFilterConfig) {
+	c.Highlight = true
+}
+
+// WithTagFilterHighlight is a functional option that renders disallowed tags
+// as escaped texts wrapped in '<span class="blocked-html">' so that they
+// are visible to readers.
+func WithTagFilterHighlight() TagFilterOption {
+	return &withTagFilterHighlight{}
+}
+
+// TagFilterHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders raw HTML with filtering tags that are disallowed in GFM.
+type TagFilterHTMLRenderer struct {
+	TagFilterConfig
+}
+
+// NewTagFilterHTMLRenderer returns a new TagFilterHTMLRenderer.
+func NewTagFilterHTMLRenderer(opts ...TagFilterOption) renderer.NodeRenderer {
+	r := &TagFilterHTMLRenderer{
+		TagFilterConfig: NewTagFilterConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetTagFilterOption(&r.TagFilterConfig)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *TagFilterHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(gast.KindRawHTML, r.renderRawHTML)
+	reg.Register(gast.KindHTMLBlock, r.renderHTMLBlock)
+}
+
+// filterTags returns the given HTML with disallowed tags filtered.
+func (r *TagFilterHTMLRenderer) filterTags(value []byte) []byte {
+	var buf bytes.Buffer
+	pos := 0
+	for pos < len(value) {
+		m := disallowedTagRegexp.FindIndex(value[pos:])
+		if m == nil {
+			break
+		}
+		start := pos + m[0]
+		stop := len(value)
+		if i := bytes.IndexByte(value[start:], '>'); i > -1 {
+			stop = start + i + 1
+		}
+		buf.Write(value[pos:start])
+		if r.Highlight {
+			buf.WriteString(`<span class="blocked-html">`)
+			buf.Write(util.EscapeHTML(value[start:stop]))
+			buf.WriteString(`</span>`)
+		} else {
+			buf.WriteString("&lt;")
+			buf.Write(value[start+1 : stop])
+		}
+		pos = stop
+	}
+	if pos == 0 {
+		return value
+	}
+	buf.Write(value[pos:])
+	return buf.Bytes()
+}
+
+func (r *TagFilterHTMLRenderer) renderRawHTML(w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if !entering {
+		return gast.WalkSkipChildren, nil
+	}
+	if r.Unsafe {
+		n := node.(*gast.RawHTML)
+		l := n.Segments.Len()
+		for i := 0; i < l; i++ {
+			segment := n.Segments.At(i)
+			_, _ = w.Write(r.filterTags(segment.Value(source)))
+		}
+		return gast.WalkSkipChildren, nil
+	}
+	_, _ = w.WriteString("<!-- raw HTML omitted -->")
+	return gast.WalkSkipChildren, nil
+}
+
+func (r *TagFilterHTMLRenderer) renderHTMLBlock(w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	n := node.(*gast.HTMLBlock)
+	if entering {
+		if r.Unsafe {
+			l := n.Lines().Len()
+			for i := 0; i < l; i++ {
+				line := n.Lines().At(i)
+				r.Writer.SecureWrite(w, r.filterTags(line.Value(source)))
+			}
+		} else {
+			_, _ = w.WriteString("<!-- raw HTML omitted -->\n")
+		}
+	} else {
+		if n.HasClosure() {
+			if r.Unsafe {
+				closure := n.ClosureLine
+				r.Writer.SecureWrite(w, r.filterTags(closure.Value(source)))
+			} else {
+				_, _ = w.WriteString("<!-- raw HTML omitted -->\n")
+			}
+		}
+	}
+	return gast.WalkContinue, nil
+}
+
+type tagFilter struct {
+	options []TagFilterOption
+}
+
+// TagFilter is an extension that filters raw HTML tags defined in
+// GFM 6.11: Disallowed Raw HTML (extension).
+var TagFilter = &tagFilter{
+	options: []TagFilterOption{},
+}
+
+// NewTagFilter returns a new extension with given options.
+func NewTagFilter(opts ...TagFilterOption) goldmark.Extender {
+	return &tagFilter{
+		options: opts,
+	}
+}
+
+func (e *tagFilter) Extend(m goldmark.Markdown) {
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewTagFilterHTMLRenderer(e.options...), 500),
+	))
+}
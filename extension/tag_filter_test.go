@@ -0,0 +1,67 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestTagFilter(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+		),
+		goldmark.WithExtensions(
+			TagFilter,
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "GFM example 652",
+			Markdown: `<strong> <title> <style> <em>
+
+<blockquote>
+  <xmp> is disallowed.  <XMP> is also disallowed.
+</blockquote>`,
+			Expected: `<p><strong> &lt;title> &lt;style> <em></p>
+<blockquote>
+  &lt;xmp> is disallowed.  &lt;XMP> is also disallowed.
+</blockquote>`,
+		},
+		t,
+	)
+}
+
+func TestTagFilterHighlight(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+		),
+		goldmark.WithExtensions(
+			NewTagFilter(
+				WithTagFilterHighlight(),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "disallowed tags are rendered as escaped texts in warning spans",
+			Markdown: `a <script>alert(1)</script> <em>b</em>
+
+<script type="text/javascript">
+alert(1);
+</script>`,
+			Expected: `<p>a <span class="blocked-html">&lt;script&gt;</span>alert(1)<span class="blocked-html">&lt;/script&gt;</span> <em>b</em></p>
+<span class="blocked-html">&lt;script type=&quot;text/javascript&quot;&gt;</span>
+alert(1);
+<span class="blocked-html">&lt;/script&gt;</span>`,
+		},
+		t,
+	)
+}
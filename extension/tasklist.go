@@ -14,13 +14,16 @@ import (
 
 var taskListRegexp = regexp.MustCompile(`^\[([\sxX])\]\s*`)
 
+// htmlTagNameRegexp matches element names that can be given by options.
+var htmlTagNameRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-]*$`)
+
 type taskCheckBoxParser struct {
 }
 
 var defaultTaskCheckBoxParser = &taskCheckBoxParser{}
 
 // NewTaskCheckBoxParser returns a new  InlineParser that can parse
-// checkboxes in list items.
+// checkboxes in list items and definition descriptions.
 // This parser must take precedence over the parser.LinkParser.
 func NewTaskCheckBoxParser() parser.InlineParser {
 	return defaultTaskCheckBoxParser
@@ -36,11 +39,18 @@ func (s *taskCheckBoxParser) Parse(parent gast.Node, block text.Reader, pc parse
 	//   - ListItem         : parent.Parent
 	//     - TextBlock      : parent
 	//       (current line)
-	if parent.Parent() == nil || parent.Parent().FirstChild() != parent {
+	// or
+	// - DefinitionList
+	//   - DefinitionDescription : parent.Parent
+	//     - TextBlock           : parent
+	//       (current line)
+	if parent.Parent() == nil || parent.Parent().FirstChild() != parent || parent.HasChildren() {
 		return nil
 	}
 
-	if _, ok := parent.Parent().(*gast.ListItem); !ok {
+	switch parent.Parent().(type) {
+	case *gast.ListItem, *ast.DefinitionDescription:
+	default:
 		return nil
 	}
 	line, _ := block.PeekLine()
@@ -58,16 +68,206 @@ func (s *taskCheckBoxParser) CloseBlock(parent gast.Node, pc parser.Context) {
 	// nothing to do
 }
 
+// TaskListConfig struct holds options for the extension.
+type TaskListConfig struct {
+	html.Config
+
+	// CheckBoxWrapper is a name of an element that wraps checkboxes.
+	CheckBoxWrapper []byte
+
+	// AsDefinitionList indicates that task lists should be rendered as
+	// definition lists that have checkboxes as terms.
+	AsDefinitionList bool
+
+	// CheckBoxEnabled indicates that checkboxes should be rendered without
+	// disabled attributes.
+	CheckBoxEnabled bool
+}
+
+// TaskListOption interface is a functional option interface for the extension.
+type TaskListOption interface {
+	renderer.Option
+	// SetTaskListOption sets given option to the extension.
+	SetTaskListOption(*TaskListConfig)
+}
+
+// NewTaskListConfig returns a new Config with defaults.
+func NewTaskListConfig() TaskListConfig {
+	return TaskListConfig{
+		Config:           html.NewConfig(),
+		CheckBoxWrapper:  nil,
+		AsDefinitionList: false,
+	}
+}
+
+// SetOption implements renderer.SetOptioner.
+func (c *TaskListConfig) SetOption(name renderer.OptionName, value interface{}) {
+	switch name {
+	case optTaskCheckBoxWrapper:
+		c.CheckBoxWrapper = value.([]byte)
+	case optTaskListAsDefinitionList:
+		c.AsDefinitionList = value.(bool)
+	case optTaskCheckBoxEnabled:
+		c.CheckBoxEnabled = value.(bool)
+	default:
+		c.Config.SetOption(name, value)
+	}
+}
+
+type withTaskListHTMLOptions struct {
+	value []html.Option
+}
+
+func (o *withTaskListHTMLOptions) SetConfig(c *renderer.Config) {
+	if o.value != nil {
+		for _, v := range o.value {
+			v.(renderer.Option).SetConfig(c)
+		}
+	}
+}
+
+func (o *withTaskListHTMLOptions) SetTaskListOption(c *TaskListConfig) {
+	if o.value != nil {
+		for _, v := range o.value {
+			v.SetHTMLOption(&c.Config)
+		}
+	}
+}
+
+// WithTaskListHTMLOptions is functional option that wraps goldmark HTMLRenderer options.
+func WithTaskListHTMLOptions(opts ...html.Option) TaskListOption {
+	return &withTaskListHTMLOptions{opts}
+}
+
+const optTaskCheckBoxWrapper renderer.OptionName = "TaskCheckBoxWrapper"
+
+type withTaskCheckBoxWrapper struct {
+	value []byte
+}
+
+func (o *withTaskCheckBoxWrapper) SetConfig(c *renderer.Config) {
+	c.Options[optTaskCheckBoxWrapper] = o.value
+}
+
+func (o *withTaskCheckBoxWrapper) SetTaskListOption(c *TaskListConfig) {
+	c.CheckBoxWrapper = o.value
+}
+
+// WithTaskCheckBoxWrapper is a functional option that wraps checkboxes
+// with the given element like '<label>'.
+// The tag must be a tag name like 'label'. Other values are ignored.
+func WithTaskCheckBoxWrapper(tag string) TaskListOption {
+	return &withTaskCheckBoxWrapper{[]byte(tag)}
+}
+
+const optTaskListAsDefinitionList renderer.OptionName = "TaskListAsDefinitionList"
+
+type withTaskListAsDefinitionList struct {
+}
+
+func (o *withTaskListAsDefinitionList) SetConfig(c *renderer.Config) {
+	c.Options[optTaskListAsDefinitionList] = true
+}
+
+func (o *withTaskListAsDefinitionList) SetTaskListOption(c *TaskListConfig) {
+	c.AsDefinitionList = true
+}
+
+// WithTaskListAsDefinitionList is a functional option that renders
+// task lists as definition lists. Checkboxes are rendered as terms and
+// contents of items are rendered as descriptions.
+func WithTaskListAsDefinitionList() TaskListOption {
+	return &withTaskListAsDefinitionList{}
+}
+
+const optTaskCheckBoxEnabled renderer.OptionName = "TaskCheckBoxEnabled"
+
+type withTaskCheckBoxEnabled struct {
+}
+
+func (o *withTaskCheckBoxEnabled) SetConfig(c *renderer.Config) {
+	c.Options[optTaskCheckBoxEnabled] = true
+}
+
+func (o *withTaskCheckBoxEnabled) SetTaskListOption(c *TaskListConfig) {
+	c.CheckBoxEnabled = true
+}
+
+// WithTaskCheckBoxEnabled is a functional option that renders checkboxes
+// without disabled attributes, so users can check them.
+func WithTaskCheckBoxEnabled() TaskListOption {
+	return &withTaskCheckBoxEnabled{}
+}
+
+type taskListDefinitionListASTTransformer struct {
+}
+
+var defaultTaskListDefinitionListASTTransformer = &taskListDefinitionListASTTransformer{}
+
+// NewTaskListDefinitionListASTTransformer returns a parser.ASTTransformer
+// that converts lists whose items all start with checkboxes into
+// ast.DefinitionList nodes.
+func NewTaskListDefinitionListASTTransformer() parser.ASTTransformer {
+	return defaultTaskListDefinitionListASTTransformer
+}
+
+func (a *taskListDefinitionListASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	var lists []*gast.List
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if list, ok := n.(*gast.List); ok && entering && isTaskList(list) {
+			lists = append(lists, list)
+		}
+		return gast.WalkContinue, nil
+	})
+	for _, list := range lists {
+		dl := ast.NewDefinitionList(0, nil)
+		for item := list.FirstChild(); item != nil; {
+			next := item.NextSibling()
+			checkBox := item.FirstChild().FirstChild()
+			term := ast.NewDefinitionTerm()
+			term.AppendChild(term, checkBox)
+			dl.AppendChild(dl, term)
+			description := ast.NewDefinitionDescription()
+			description.IsTight = list.IsTight
+			for c := item.FirstChild(); c != nil; {
+				nc := c.NextSibling()
+				if c.HasChildren() || c.Kind() != gast.KindTextBlock {
+					description.AppendChild(description, c)
+				}
+				c = nc
+			}
+			dl.AppendChild(dl, description)
+			item = next
+		}
+		list.Parent().ReplaceChild(list.Parent(), list, dl)
+	}
+}
+
+// isTaskList returns true if all items of the given list start with
+// checkboxes.
+func isTaskList(list *gast.List) bool {
+	if !list.HasChildren() {
+		return false
+	}
+	for item := list.FirstChild(); item != nil; item = item.NextSibling() {
+		fc := item.FirstChild()
+		if fc == nil || fc.FirstChild() == nil || fc.FirstChild().Kind() != ast.KindTaskCheckBox {
+			return false
+		}
+	}
+	return true
+}
+
 // TaskCheckBoxHTMLRenderer is a renderer.NodeRenderer implementation that
 // renders checkboxes in list items.
 type TaskCheckBoxHTMLRenderer struct {
-	html.Config
+	TaskListConfig
 }
 
 // NewTaskCheckBoxHTMLRenderer returns a new TaskCheckBoxHTMLRenderer.
 func NewTaskCheckBoxHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
 	r := &TaskCheckBoxHTMLRenderer{
-		Config: html.NewConfig(),
+		TaskListConfig: NewTaskListConfig(),
 	}
 	for _, opt := range opts {
 		opt.SetHTMLOption(&r.Config)
@@ -75,6 +275,18 @@ func NewTaskCheckBoxHTMLRenderer(opts ...html.Option) renderer.NodeRenderer {
 	return r
 }
 
+// NewTaskCheckBoxHTMLRendererWithOptions returns a new TaskCheckBoxHTMLRenderer
+// with given TaskListOptions.
+func NewTaskCheckBoxHTMLRendererWithOptions(opts ...TaskListOption) renderer.NodeRenderer {
+	r := &TaskCheckBoxHTMLRenderer{
+		TaskListConfig: NewTaskListConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetTaskListOption(&r.TaskListConfig)
+	}
+	return r
+}
+
 // RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
 func (r *TaskCheckBoxHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
 	reg.Register(ast.KindTaskCheckBox, r.renderTaskCheckBox)
@@ -86,30 +298,75 @@ func (r *TaskCheckBoxHTMLRenderer) renderTaskCheckBox(w util.BufWriter, source [
 	}
 	n := node.(*ast.TaskCheckBox)
 
+	wrapper := r.CheckBoxWrapper
+	if wrapper != nil && !htmlTagNameRegexp.Match(wrapper) {
+		wrapper = nil
+	}
+	if wrapper != nil {
+		_ = w.WriteByte('<')
+		_, _ = w.Write(wrapper)
+		_ = w.WriteByte('>')
+	}
+	_, _ = w.WriteString(`<input`)
 	if n.IsChecked {
-		w.WriteString(`<input checked="" disabled="" type="checkbox"`)
-	} else {
-		w.WriteString(`<input disabled="" type="checkbox"`)
+		_, _ = w.WriteString(` checked=""`)
+	}
+	if !r.CheckBoxEnabled {
+		_, _ = w.WriteString(` disabled=""`)
 	}
+	_, _ = w.WriteString(` type="checkbox"`)
 	if r.XHTML {
-		w.WriteString(" /> ")
+		w.WriteString(" />")
 	} else {
-		w.WriteString("> ")
+		w.WriteString(">")
+	}
+	if wrapper != nil {
+		_, _ = w.WriteString("</")
+		_, _ = w.Write(wrapper)
+		_ = w.WriteByte('>')
+	}
+	// checkboxes in terms of definition lists are not followed by texts.
+	if n.Parent().Kind() != ast.KindDefinitionTerm {
+		_ = w.WriteByte(' ')
 	}
 	return gast.WalkContinue, nil
 }
 
 type taskList struct {
+	options []TaskListOption
 }
 
 // TaskList is an extension that allow you to use GFM task lists.
-var TaskList = &taskList{}
+var TaskList = &taskList{
+	options: []TaskListOption{},
+}
+
+// NewTaskList returns a new extension with given options.
+func NewTaskList(opts ...TaskListOption) goldmark.Extender {
+	return &taskList{
+		options: opts,
+	}
+}
 
 func (e *taskList) Extend(m goldmark.Markdown) {
 	m.Parser().AddOptions(parser.WithInlineParsers(
 		util.Prioritized(NewTaskCheckBoxParser(), 0),
 	))
 	m.Renderer().AddOptions(renderer.WithNodeRenderers(
-		util.Prioritized(NewTaskCheckBoxHTMLRenderer(), 500),
+		util.Prioritized(NewTaskCheckBoxHTMLRendererWithOptions(e.options...), 500),
 	))
+	config := NewTaskListConfig()
+	for _, opt := range e.options {
+		opt.SetTaskListOption(&config)
+	}
+	if config.AsDefinitionList {
+		m.Parser().AddOptions(parser.WithASTTransformers(
+			util.Prioritized(NewTaskListDefinitionListASTTransformer(), 999),
+		))
+		// the renderer of the DefinitionList extension takes precedence over
+		// this renderer if it is also enabled.
+		m.Renderer().AddOptions(renderer.WithNodeRenderers(
+			util.Prioritized(NewDefinitionListHTMLRenderer(), 501),
+		))
+	}
 }
@@ -4,8 +4,11 @@ import (
 	"testing"
 
 	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
 	"github.com/yuin/goldmark/renderer/html"
 	"github.com/yuin/goldmark/testutil"
+	"github.com/yuin/goldmark/text"
 )
 
 func TestTaskList(t *testing.T) {
@@ -19,3 +22,175 @@ func TestTaskList(t *testing.T) {
 	)
 	testutil.DoTestCaseFile(markdown, "_test/tasklist.txt", t, testutil.ParseCliCaseArg()...)
 }
+
+func TestTaskListInDefinitionDescription(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+		),
+		goldmark.WithExtensions(
+			TaskList,
+			DefinitionList,
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "checkboxes in definition descriptions",
+			Markdown: `Term
+: - [x] done
+  - [ ] todo
+
+Term2
+: [x] direct
+: not [x] leading`,
+			Expected: `<dl>
+<dt>Term</dt>
+<dd><ul>
+<li><input checked="" disabled="" type="checkbox"> done</li>
+<li><input disabled="" type="checkbox"> todo</li>
+</ul>
+</dd>
+<dt>Term2</dt>
+<dd><input checked="" disabled="" type="checkbox"> direct</dd>
+<dd>not [x] leading</dd>
+</dl>`,
+		},
+		t,
+	)
+}
+
+func TestTaskCheckBoxWrapper(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewTaskList(
+				WithTaskCheckBoxWrapper("label"),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "checkboxes are wrapped with the given element",
+			Markdown: `- [x] foo
+- [ ] bar`,
+			Expected: `<ul>
+<li><label><input checked="" disabled="" type="checkbox"></label> foo</li>
+<li><label><input disabled="" type="checkbox"></label> bar</li>
+</ul>`,
+		},
+		t,
+	)
+}
+
+func TestTaskCheckBoxWrapperIsValidated(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewTaskList(
+				WithTaskCheckBoxWrapper(`label><script>alert(1)</script`),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "wrappers that are not tag names are ignored",
+			Markdown:    `- [x] foo`,
+			Expected: `<ul>
+<li><input checked="" disabled="" type="checkbox"> foo</li>
+</ul>`,
+		},
+		t,
+	)
+}
+
+func TestTaskListAsDefinitionList(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewTaskList(
+				WithTaskListAsDefinitionList(),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "task lists are rendered as definition lists",
+			Markdown: `- [x] foo
+- [ ] bar
+  baz
+
+* [ ] loose
+
+* [x] list
+
+- [x] not
+- a task list`,
+			Expected: `<dl>
+<dt><input checked="" disabled="" type="checkbox"></dt>
+<dd>foo</dd>
+<dt><input disabled="" type="checkbox"></dt>
+<dd>bar
+baz</dd>
+</dl>
+<dl>
+<dt><input disabled="" type="checkbox"></dt>
+<dd>
+<p>loose</p>
+</dd>
+<dt><input checked="" disabled="" type="checkbox"></dt>
+<dd>
+<p>list</p>
+</dd>
+</dl>
+<ul>
+<li><input checked="" disabled="" type="checkbox"> not</li>
+<li>a task list</li>
+</ul>`,
+		},
+		t,
+	)
+}
+
+func TestTaskCheckBoxEnabled(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewTaskList(
+				WithTaskCheckBoxEnabled(),
+			),
+		),
+	)
+	source := `- [x] foo
+- [ ] bar
+- [X] baz`
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "checkboxes are rendered without disabled attributes",
+			Markdown:    source,
+			Expected: `<ul>
+<li><input checked="" type="checkbox"> foo</li>
+<li><input type="checkbox"> bar</li>
+<li><input checked="" type="checkbox"> baz</li>
+</ul>`,
+		},
+		t,
+	)
+
+	doc := markdown.Parser().Parse(text.NewReader([]byte(source)))
+	checked := 0
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if cb, ok := n.(*east.TaskCheckBox); ok && entering && cb.IsChecked {
+			checked++
+		}
+		return ast.WalkContinue, nil
+	})
+	if checked != 2 {
+		t.Errorf("2 checkboxes must be checked, but got %d", checked)
+	}
+}
@@ -0,0 +1,95 @@
+package extension
+
+import (
+	"bytes"
+
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer/text"
+)
+
+// TOCExcludeClass is a class name of headings that are not listed in
+// tables of contents like '## Heading {.no-toc}'.
+var TOCExcludeClass = []byte("no-toc")
+
+// A TOC struct represents a table of contents of a document.
+type TOC struct {
+	// Items is a list of top level headings.
+	Items []*TOCItem
+}
+
+// A TOCItem struct represents a heading in a table of contents.
+type TOCItem struct {
+	// Level is a level of the heading.
+	Level int
+
+	// Title is a plain text of the heading.
+	Title []byte
+
+	// ID is an id of the heading. ID is nil if the heading does not
+	// have an id.
+	ID []byte
+
+	// Items is a list of headings under this heading.
+	Items []*TOCItem
+}
+
+// TableOfContents returns a table of contents of the given document.
+// Headings are nested according to their levels, so an h3 heading
+// after an h2 heading is listed as an item of the h2 heading.
+// Headings that have the TOCExcludeClass class are skipped.
+// Use parser.WithAutoHeadingID to give ids to headings.
+func TableOfContents(doc gast.Node, source []byte) *TOC {
+	toc := &TOC{}
+	var stack []*TOCItem
+	r := text.NewRenderer()
+	_ = gast.Walk(doc, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		heading, ok := n.(*gast.Heading)
+		if !ok {
+			return gast.WalkContinue, nil
+		}
+		if isTOCExcluded(heading) {
+			return gast.WalkSkipChildren, nil
+		}
+		var buf bytes.Buffer
+		_ = r.Render(&buf, source, heading)
+		item := &TOCItem{
+			Level: heading.Level,
+			Title: buf.Bytes(),
+		}
+		if id, ok := heading.AttributeString("id"); ok {
+			item.ID = id.([]byte)
+		}
+		for len(stack) != 0 && stack[len(stack)-1].Level >= item.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			toc.Items = append(toc.Items, item)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Items = append(parent.Items, item)
+		}
+		stack = append(stack, item)
+		return gast.WalkSkipChildren, nil
+	})
+	return toc
+}
+
+func isTOCExcluded(heading *gast.Heading) bool {
+	v, ok := heading.AttributeString("class")
+	if !ok {
+		return false
+	}
+	class, ok := v.([]byte)
+	if !ok {
+		return false
+	}
+	for _, c := range bytes.Fields(class) {
+		if bytes.Equal(c, TOCExcludeClass) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package extension
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+func dumpTOCItems(b *strings.Builder, items []*TOCItem, level int) {
+	for _, item := range items {
+		fmt.Fprintf(b, "%s%d %q #%s\n", strings.Repeat("  ", level), item.Level, item.Title, item.ID)
+		dumpTOCItems(b, item.Items, level+1)
+	}
+}
+
+func TestTableOfContents(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+			parser.WithAttribute(),
+		),
+	)
+	source := []byte(`# Title
+
+## Section *one* &amp; ` + "`code`" + `
+
+### Sub section
+
+## Hidden {.no-toc}
+
+### Under hidden
+
+Section
+two
+---
+
+#### Deep
+
+> # In a blockquote
+
+### Last {#custom}
+`)
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	toc := TableOfContents(doc, source)
+	var b strings.Builder
+	dumpTOCItems(&b, toc.Items, 0)
+	expected := `1 "Title" #title
+  2 "Section one & code" #section-one-amp-code
+    3 "Sub section" #sub-section
+    3 "Under hidden" #under-hidden
+  2 "Section two" #two
+    4 "Deep" #deep
+1 "In a blockquote" #in-a-blockquote
+  3 "Last" #custom
+`
+	if b.String() != expected {
+		t.Errorf("expected:\n%s\nbut got:\n%s", expected, b.String())
+	}
+}
+
+func TestTableOfContentsPlainTextTitles(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+		),
+	)
+	source := []byte("## **Bold** Title\n")
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	toc := TableOfContents(doc, source)
+	if len(toc.Items) != 1 || string(toc.Items[0].Title) != "Bold Title" {
+		var b strings.Builder
+		dumpTOCItems(&b, toc.Items, 0)
+		t.Fatalf("a TOC title should be a plain text, but got:\n%s", b.String())
+	}
+	var b strings.Builder
+	if err := markdown.Renderer().Render(&b, source, doc); err != nil {
+		t.Fatal(err)
+	}
+	expected := "<h2 id=\"bold-title\"><strong>Bold</strong> Title</h2>\n"
+	if b.String() != expected {
+		t.Errorf("a heading should keep its markup, expected %q but got %q", expected, b.String())
+	}
+}
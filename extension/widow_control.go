@@ -0,0 +1,155 @@
+package extension
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var nbsp = []byte("&nbsp;")
+
+type widowControlASTTransformer struct {
+	prepositions [][]byte
+}
+
+// NewWidowControlASTTransformer returns a parser.ASTTransformer that
+// replaces a space before the last word of each paragraph with a
+// non-breaking space. Spaces after the given prepositions are replaced too.
+func NewWidowControlASTTransformer(prepositions ...string) parser.ASTTransformer {
+	t := &widowControlASTTransformer{}
+	for _, p := range prepositions {
+		t.prepositions = append(t.prepositions, bytes.ToLower([]byte(p)))
+	}
+	return t
+}
+
+func (a *widowControlASTTransformer) Transform(node *gast.Document, reader text.Reader, pc parser.Context) {
+	source := reader.Source()
+	var paragraphs []gast.Node
+	_ = gast.Walk(node, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if entering && n.Kind() == gast.KindParagraph {
+			paragraphs = append(paragraphs, n)
+			return gast.WalkSkipChildren, nil
+		}
+		return gast.WalkContinue, nil
+	})
+	for _, p := range paragraphs {
+		a.transformParagraph(p, source)
+	}
+}
+
+func (a *widowControlASTTransformer) transformParagraph(p gast.Node, source []byte) {
+	var texts []*gast.Text
+	_ = gast.Walk(p, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		switch n.Kind() {
+		case gast.KindCodeSpan, gast.KindLink, gast.KindAutoLink, gast.KindImage, gast.KindRawHTML:
+			return gast.WalkSkipChildren, nil
+		case gast.KindText:
+			if t := n.(*gast.Text); !t.IsRaw() {
+				texts = append(texts, t)
+			}
+		}
+		return gast.WalkContinue, nil
+	})
+	if len(texts) == 0 {
+		return
+	}
+	positions := map[*gast.Text][]int{}
+	if len(a.prepositions) != 0 {
+		for _, t := range texts {
+			positions[t] = a.prepositionPositions(t, source)
+		}
+	}
+	last := texts[len(texts)-1]
+	if !last.SoftLineBreak() && !last.HardLineBreak() {
+		value := last.Segment.Value(source)
+		i := bytes.LastIndexByte(value, ' ')
+		if i > 0 && i < len(value)-1 && value[i-1] != ' ' {
+			pos := last.Segment.Start + i
+			if !containsInt(positions[last], pos) {
+				positions[last] = append(positions[last], pos)
+			}
+		}
+	}
+	for t, ps := range positions {
+		if len(ps) == 0 {
+			continue
+		}
+		sort.Ints(ps)
+		parent := t.Parent()
+		segment := t.Segment
+		start := segment.Start
+		for i, pos := range ps {
+			var s text.Segment
+			if i == 0 {
+				s = segment.WithStop(pos)
+			} else {
+				s = text.NewSegment(start, pos)
+			}
+			parent.InsertBefore(parent, t, gast.NewTextSegment(s))
+			str := gast.NewString(nbsp)
+			str.SetCode(true)
+			parent.InsertBefore(parent, t, str)
+			start = pos + 1
+		}
+		t.Segment = text.NewSegment(start, segment.Stop)
+	}
+}
+
+func (a *widowControlASTTransformer) prepositionPositions(t *gast.Text, source []byte) []int {
+	var ret []int
+	value := t.Segment.Value(source)
+	start := 0
+	for i := 0; i < len(value); i++ {
+		if value[i] != ' ' {
+			continue
+		}
+		word := value[start:i]
+		start = i + 1
+		if len(word) == 0 || i == len(value)-1 || value[i+1] == ' ' {
+			continue
+		}
+		for _, p := range a.prepositions {
+			if bytes.Equal(bytes.ToLower(word), p) {
+				ret = append(ret, t.Segment.Start+i)
+				break
+			}
+		}
+	}
+	return ret
+}
+
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+type widowControl struct {
+	prepositions []string
+}
+
+// WithWidowControl returns an extension that inserts a non-breaking space
+// before the last word of each paragraph to avoid widows.
+// Spaces after the given short prepositions(e.g. "a", "in") are replaced with
+// non-breaking spaces too. Code spans and links are never modified.
+func WithWidowControl(prepositions ...string) goldmark.Extender {
+	return &widowControl{prepositions}
+}
+
+func (e *widowControl) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(parser.WithASTTransformers(
+		util.Prioritized(NewWidowControlASTTransformer(e.prepositions...), 500),
+	))
+}
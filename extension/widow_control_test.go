@@ -0,0 +1,52 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestWidowControl(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			WithWidowControl(),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "A non-breaking space should be inserted before the last word",
+			Markdown: `This is a paragraph with a widow.
+
+Words in *emphasis text*
+
+Ends with ` + "`code span`" + `
+
+Ends with [a link](/url)
+`,
+			Expected: `<p>This is a paragraph with a&nbsp;widow.</p>
+<p>Words in <em>emphasis&nbsp;text</em></p>
+<p>Ends with <code>code span</code></p>
+<p>Ends with <a href="/url">a link</a></p>`,
+		},
+		t,
+	)
+
+	markdown = goldmark.New(
+		goldmark.WithExtensions(
+			WithWidowControl("a", "in"),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "Non-breaking spaces should be inserted after prepositions",
+			Markdown:    `A cat sat in a box and slept.`,
+			Expected:    `<p>A&nbsp;cat sat in&nbsp;a&nbsp;box and&nbsp;slept.</p>`,
+		},
+		t,
+	)
+}
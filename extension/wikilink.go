@@ -0,0 +1,244 @@
+package extension
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// WikilinkConfig struct holds options for the extension.
+type WikilinkConfig struct {
+	html.Config
+
+	// Resolver converts targets of wikilinks into URLs.
+	Resolver func(target []byte) []byte
+}
+
+// WikilinkOption interface is a functional option interface for the extension.
+type WikilinkOption interface {
+	renderer.Option
+	// SetWikilinkOption sets given option to the extension.
+	SetWikilinkOption(*WikilinkConfig)
+}
+
+// NewWikilinkConfig returns a new Config with defaults.
+func NewWikilinkConfig() WikilinkConfig {
+	return WikilinkConfig{
+		Config:   html.NewConfig(),
+		Resolver: SlugifyWikilinkTarget,
+	}
+}
+
+// SetOption implements renderer.SetOptioner.
+func (c *WikilinkConfig) SetOption(name renderer.OptionName, value interface{}) {
+	switch name {
+	case optWikilinkResolver:
+		c.Resolver = value.(func([]byte) []byte)
+	default:
+		c.Config.SetOption(name, value)
+	}
+}
+
+type withWikilinkHTMLOptions struct {
+	value []html.Option
+}
+
+func (o *withWikilinkHTMLOptions) SetConfig(c *renderer.Config) {
+	for _, v := range o.value {
+		v.(renderer.Option).SetConfig(c)
+	}
+}
+
+func (o *withWikilinkHTMLOptions) SetWikilinkOption(c *WikilinkConfig) {
+	for _, v := range o.value {
+		v.SetHTMLOption(&c.Config)
+	}
+}
+
+// WithWikilinkHTMLOptions is functional option that wraps goldmark HTMLRenderer options.
+func WithWikilinkHTMLOptions(opts ...html.Option) WikilinkOption {
+	return &withWikilinkHTMLOptions{opts}
+}
+
+const optWikilinkResolver renderer.OptionName = "WikilinkResolver"
+
+type withWikilinkResolver struct {
+	value func([]byte) []byte
+}
+
+func (o *withWikilinkResolver) SetConfig(c *renderer.Config) {
+	c.Options[optWikilinkResolver] = o.value
+}
+
+func (o *withWikilinkResolver) SetWikilinkOption(c *WikilinkConfig) {
+	c.Resolver = o.value
+}
+
+// WithWikilinkResolver is a functional option that converts targets of
+// wikilinks into URLs by the given function.
+// SlugifyWikilinkTarget is used by default.
+func WithWikilinkResolver(resolver func(target []byte) []byte) WikilinkOption {
+	return &withWikilinkResolver{resolver}
+}
+
+// SlugifyWikilinkTarget converts the given target into a slug like
+// 'page-name'. ASCII letters are lowercased and runs of spaces, '-' and '_'
+// are replaced with a single '-'. Other ASCII punctuations are removed.
+func SlugifyWikilinkTarget(target []byte) []byte {
+	result := make([]byte, 0, len(target))
+	hyphen := false
+	for _, c := range target {
+		switch {
+		case util.IsSpace(c) || c == '-' || c == '_':
+			hyphen = len(result) != 0
+			continue
+		case c < 0x80 && !util.IsAlphaNumeric(c):
+			continue
+		}
+		if hyphen {
+			result = append(result, '-')
+			hyphen = false
+		}
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+type wikilinkParser struct {
+}
+
+var defaultWikilinkParser = &wikilinkParser{}
+
+// NewWikilinkParser return a new InlineParser that parses wikilinks like
+// '[[Target]]' and '[[Target|Label]]'.
+func NewWikilinkParser() parser.InlineParser {
+	return defaultWikilinkParser
+}
+
+func (s *wikilinkParser) Trigger() []byte {
+	return []byte{'['}
+}
+
+func (s *wikilinkParser) Parse(parent gast.Node, block text.Reader, pc parser.Context) gast.Node {
+	line, segment := block.PeekLine()
+	if len(line) < 5 || line[1] != '[' {
+		return nil
+	}
+	closer := bytes.Index(line[2:], []byte("]]"))
+	if closer < 0 {
+		return nil
+	}
+	value := line[2 : closer+2]
+	if bytes.IndexByte(value, '[') > -1 || bytes.IndexByte(value, ']') > -1 {
+		return nil
+	}
+	source := block.Source()
+	targetSegment := text.NewSegment(segment.Start+2, segment.Start+2+len(value))
+	labelSegment := targetSegment
+	hasLabel := false
+	if i := bytes.IndexByte(value, '|'); i > -1 {
+		hasLabel = true
+		labelSegment = targetSegment.WithStart(targetSegment.Start + i + 1)
+		targetSegment = targetSegment.WithStop(targetSegment.Start + i)
+	}
+	targetSegment = targetSegment.TrimLeftSpace(source)
+	targetSegment = targetSegment.TrimRightSpace(source)
+	labelSegment = labelSegment.TrimLeftSpace(source)
+	labelSegment = labelSegment.TrimRightSpace(source)
+	if targetSegment.IsEmpty() || labelSegment.IsEmpty() {
+		return nil
+	}
+	var label []byte
+	if hasLabel {
+		label = labelSegment.Value(source)
+	}
+	node := ast.NewWikilink(targetSegment.Value(source), label)
+	node.AppendChild(node, gast.NewTextSegment(labelSegment))
+	block.Advance(closer + 4)
+	return node
+}
+
+func (s *wikilinkParser) CloseBlock(parent gast.Node, pc parser.Context) {
+	// nothing to do
+}
+
+// WikilinkHTMLRenderer is a renderer.NodeRenderer implementation that
+// renders Wikilink nodes.
+type WikilinkHTMLRenderer struct {
+	WikilinkConfig
+}
+
+// NewWikilinkHTMLRenderer returns a new WikilinkHTMLRenderer.
+func NewWikilinkHTMLRenderer(opts ...WikilinkOption) renderer.NodeRenderer {
+	r := &WikilinkHTMLRenderer{
+		WikilinkConfig: NewWikilinkConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetWikilinkOption(&r.WikilinkConfig)
+	}
+	return r
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.RegisterFuncs.
+func (r *WikilinkHTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindWikilink, r.renderWikilink)
+}
+
+// WikilinkAttributeFilter defines attribute names which wikilink elements can have.
+var WikilinkAttributeFilter = html.LinkAttributeFilter
+
+func (r *WikilinkHTMLRenderer) renderWikilink(w util.BufWriter, source []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if entering {
+		n := node.(*ast.Wikilink)
+		destination := r.Resolver(n.Target)
+		_, _ = w.WriteString(`<a href="`)
+		if r.Unsafe || !html.IsDangerousURL(destination) {
+			_, _ = w.Write(util.EscapeHTML(util.URLEscape(destination, true)))
+		}
+		_ = w.WriteByte('"')
+		if n.Attributes() != nil {
+			html.RenderAttributes(w, n, WikilinkAttributeFilter)
+		}
+		_ = w.WriteByte('>')
+	} else {
+		_, _ = w.WriteString("</a>")
+	}
+	return gast.WalkContinue, nil
+}
+
+type wikilink struct {
+	options []WikilinkOption
+}
+
+// Wikilink is an extension that allow you to use wikilinks like
+// '[[Target]]' and '[[Target|Label]]'.
+var Wikilink = &wikilink{
+	options: []WikilinkOption{},
+}
+
+// NewWikilink returns a new extension with given options.
+func NewWikilink(opts ...WikilinkOption) goldmark.Extender {
+	return &wikilink{
+		options: opts,
+	}
+}
+
+func (e *wikilink) Extend(m goldmark.Markdown) {
+	// wikilinks must be parsed before links.
+	m.Parser().AddOptions(parser.WithInlineParsers(
+		util.Prioritized(NewWikilinkParser(), 199),
+	))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(NewWikilinkHTMLRenderer(e.options...), 500),
+	))
+}
@@ -0,0 +1,39 @@
+package extension
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestWikilink(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			Wikilink,
+		),
+	)
+	testutil.DoTestCaseFile(markdown, "_test/wikilink.txt", t, testutil.ParseCliCaseArg()...)
+}
+
+func TestWikilinkResolver(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithExtensions(
+			NewWikilink(
+				WithWikilinkResolver(func(target []byte) []byte {
+					return append([]byte("/wiki/"), target...)
+				}),
+			),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Targets are resolved by the given function",
+			Markdown:    "[[Page Name|label]] [[javascript:alert(1)]]",
+			Expected:    `<p><a href="/wiki/Page%20Name">label</a> <a href="/wiki/javascript:alert(1)">javascript:alert(1)</a></p>`,
+		},
+		t,
+	)
+}
@@ -2,15 +2,21 @@ package goldmark_test
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"fmt"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	. "github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
 	"github.com/yuin/goldmark/renderer/html"
 	"github.com/yuin/goldmark/testutil"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
 )
 
 func TestExtras(t *testing.T) {
@@ -88,11 +94,198 @@ func TestAutogeneratedIDs(t *testing.T) {
 	}
 }
 
+func TestReferences(t *testing.T) {
+	ctx := parser.NewContext()
+	markdown := New()
+	source := []byte(`[used]
+
+[zzz]: /zzz
+[used]: /used 'title'
+[aaa]: <a a>
+[USED]: /duplicated
+`)
+	var b bytes.Buffer
+	err := markdown.Convert(source, &b, parser.WithContext(ctx))
+	if err != nil {
+		t.Error(err.Error())
+	}
+	expected := []string{
+		"Reference{Label:zzz, Destination:/zzz, Title:}",
+		"Reference{Label:used, Destination:/used, Title:title}",
+		"Reference{Label:aaa, Destination:a a, Title:}",
+	}
+	refs := ctx.References()
+	if len(refs) != len(expected) {
+		t.Fatalf("expected %d references, but got %d", len(expected), len(refs))
+	}
+	for i, ref := range refs {
+		if ref.String() != expected[i] {
+			t.Errorf("expected %s, but got %s", expected[i], ref.String())
+		}
+	}
+}
+
+type mapCache struct {
+	entries map[[sha256.Size]byte]*parser.CacheEntry
+}
+
+func (c *mapCache) Get(hash [sha256.Size]byte) (*parser.CacheEntry, bool) {
+	e, ok := c.entries[hash]
+	return e, ok
+}
+
+func (c *mapCache) Put(hash [sha256.Size]byte, entry *parser.CacheEntry) {
+	c.entries[hash] = entry
+}
+
+type spyASTTransformer struct {
+	count int
+}
+
+func (s *spyASTTransformer) Transform(node *ast.Document, reader text.Reader, pc parser.Context) {
+	s.count++
+}
+
+func TestCache(t *testing.T) {
+	spy := &spyASTTransformer{}
+	markdown := New(WithParserOptions(
+		parser.WithCache(&mapCache{entries: map[[sha256.Size]byte]*parser.CacheEntry{}}),
+		parser.WithASTTransformers(util.Prioritized(spy, 0)),
+	))
+	for i, c := range []struct {
+		source   string
+		expected string
+		count    int
+	}{
+		{"# Title\n\n*text*", "<h1>Title</h1>\n<p><em>text</em></p>\n", 1},
+		{"# Title\n\n*text*", "<h1>Title</h1>\n<p><em>text</em></p>\n", 1},
+		{"other *text*", "<p>other <em>text</em></p>\n", 2},
+		{"# Title\n\n*text*", "<h1>Title</h1>\n<p><em>text</em></p>\n", 2},
+	} {
+		var b bytes.Buffer
+		if err := markdown.Convert([]byte(c.source), &b); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != c.expected {
+			t.Errorf("%d: expected %q, but got %q", i, c.expected, b.String())
+		}
+		if spy.count != c.count {
+			t.Errorf("%d: sources must be parsed %d times, but parsed %d times", i, c.count, spy.count)
+		}
+	}
+}
+
+func TestCacheRestoresContext(t *testing.T) {
+	markdown := New(WithParserOptions(
+		parser.WithAutoHeadingID(),
+		parser.WithCache(&mapCache{entries: map[[sha256.Size]byte]*parser.CacheEntry{}}),
+	))
+	source := []byte("# Title\n\n[a][ref]\n\n[ref]: /url\n")
+	for i := 0; i < 2; i++ {
+		pc := parser.NewContext()
+		var b bytes.Buffer
+		if err := markdown.Convert(source, &b, parser.WithContext(pc)); err != nil {
+			t.Fatal(err)
+		}
+		refs := pc.References()
+		if len(refs) != 1 || string(refs[0].Destination()) != "/url" {
+			t.Errorf("%d: references must be restored, but got %v", i, refs)
+		}
+		if id := pc.IDs().Generate([]byte("Title"), ast.KindHeading); string(id) != "title-1" {
+			t.Errorf("%d: used ids must be restored, but got %q", i, id)
+		}
+	}
+}
+
 func nowMillis() int64 {
 	// TODO: replace UnixNano to UnixMillis(drops Go1.16 support)
 	return time.Now().UnixNano() / 1000000
 }
 
+type spyWriter struct {
+	writes  int
+	maxSize int
+	size    int
+}
+
+func (w *spyWriter) Write(p []byte) (int, error) {
+	w.writes++
+	w.size += len(p)
+	if len(p) > w.maxSize {
+		w.maxSize = len(p)
+	}
+	return len(p), nil
+}
+
+func TestBufferSize(t *testing.T) {
+	markdown := New(WithRendererOptions(
+		renderer.WithBufferSize(256),
+	))
+	line := "<" + strings.Repeat("a", 30) + ">\n"
+	source := []byte("```\n" + strings.Repeat(line, 10000) + "```\n")
+	var expected bytes.Buffer
+	if err := New().Convert(source, &expected); err != nil {
+		t.Fatal(err)
+	}
+	w := &spyWriter{}
+	if err := markdown.Convert(source, w); err != nil {
+		t.Fatal(err)
+	}
+	if w.size != expected.Len() {
+		t.Errorf("%d bytes expected, but got %d bytes", expected.Len(), w.size)
+	}
+	if w.maxSize > 256 {
+		t.Errorf("output must be flushed every 256 bytes, but got a write of %d bytes", w.maxSize)
+	}
+	if w.writes < expected.Len()/256 {
+		t.Errorf("output must be written incrementally, but got only %d writes", w.writes)
+	}
+}
+
+func TestConcurrentConvert(t *testing.T) {
+	markdown := New(WithParserOptions(
+		parser.WithAutoHeadingID(),
+		parser.WithAttribute(),
+	))
+	source := func(i int) []byte {
+		return []byte(fmt.Sprintf("# Heading %d {.c%d}\n\n# Heading %d\n\n- *a* [b][ref%d]\n\n[ref%d]: /url%d\n", i, i, i, i, i, i))
+	}
+	expected := make([]string, 100)
+	for i := range expected {
+		var b bytes.Buffer
+		if err := markdown.Convert(source(i), &b); err != nil {
+			t.Fatal(err)
+		}
+		expected[i] = b.String()
+	}
+	pool := parser.NewContextPool()
+	var wg sync.WaitGroup
+	for i := 0; i < len(expected); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var b, pooled bytes.Buffer
+			if err := markdown.Convert(source(i), &b); err != nil {
+				t.Error(err)
+				return
+			}
+			pc := pool.Get()
+			if err := markdown.ConvertWithContext(source(i), &pooled, pc); err != nil {
+				t.Error(err)
+				return
+			}
+			pool.Put(pc)
+			if b.String() != expected[i] {
+				t.Errorf("%d: expected %q, but got %q", i, expected[i], b.String())
+			}
+			if pooled.String() != expected[i] {
+				t.Errorf("%d: expected %q with a pooled context, but got %q", i, expected[i], pooled.String())
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
 func TestDeepNestedLabelPerformance(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping performance test in short mode")
@@ -33,11 +33,21 @@ func Convert(source []byte, w io.Writer, opts ...parser.ParseOption) error {
 
 // A Markdown interface offers functions to convert Markdown text to
 // a desired format.
+//
+// Convert and ConvertWithContext are safe for concurrent use by multiple
+// goroutines once a Markdown is configured. Each call of Convert allocates
+// a fresh parser.Context unless parser.WithContext is given, so a Context
+// must not be shared between concurrent calls.
 type Markdown interface {
 	// Convert interprets a UTF-8 bytes source in Markdown and write rendered
 	// contents to a writer w.
 	Convert(source []byte, writer io.Writer, opts ...parser.ParseOption) error
 
+	// ConvertWithContext is same as Convert, but uses the given parser.Context
+	// to parse the source. This is useful for reusing Contexts with a
+	// parser.ContextPool.
+	ConvertWithContext(source []byte, writer io.Writer, pc parser.Context, opts ...parser.ParseOption) error
+
 	// Parser returns a Parser that will be used for conversion.
 	Parser() parser.Parser
 
@@ -117,6 +127,12 @@ func (m *markdown) Convert(source []byte, writer io.Writer, opts ...parser.Parse
 	return m.renderer.Render(writer, source, doc)
 }
 
+func (m *markdown) ConvertWithContext(source []byte, writer io.Writer, pc parser.Context, opts ...parser.ParseOption) error {
+	options := make([]parser.ParseOption, 0, len(opts)+1)
+	options = append(options, opts...)
+	return m.Convert(source, writer, append(options, parser.WithContext(pc))...)
+}
+
 func (m *markdown) Parser() parser.Parser {
 	return m.parser
 }
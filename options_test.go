@@ -1,11 +1,18 @@
 package goldmark_test
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"strings"
 	"testing"
 
 	. "github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/html"
 	"github.com/yuin/goldmark/testutil"
+	"github.com/yuin/goldmark/text"
 )
 
 func TestAttributeAndAutoHeadingID(t *testing.T) {
@@ -17,3 +24,1224 @@ func TestAttributeAndAutoHeadingID(t *testing.T) {
 	)
 	testutil.DoTestCaseFile(markdown, "_test/options.txt", t, testutil.ParseCliCaseArg()...)
 }
+
+func TestAttributeOrderIsStable(t *testing.T) {
+	markdown := New(
+		WithParserOptions(
+			parser.WithAttribute(),
+		),
+	)
+	source := []byte("# Test {data-z=\"1\" title=\"t\" #top lang=en .b .a}\n")
+	expected := []byte(`<h1 data-z="1" title="t" id="top" lang="en" class="b a">Test</h1>` + "\n")
+	for i := 0; i < 100; i++ {
+		var out bytes.Buffer
+		if err := markdown.Convert(source, &out); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(out.Bytes(), expected) {
+			t.Fatalf("run %d: unexpected attribute order: %s", i, out.String())
+		}
+	}
+}
+
+func TestHeadingIDFunc(t *testing.T) {
+	markdown := New(
+		WithParserOptions(
+			parser.WithAutoHeadingID(),
+			parser.WithAttribute(),
+			parser.WithHeadingIDFunc(func(value []byte) []byte {
+				value = bytes.ReplaceAll(value, []byte("é"), []byte("e"))
+				return bytes.ReplaceAll(bytes.ToLower(value), []byte(" "), []byte("_"))
+			}),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "ids are generated by the given function",
+			Markdown: `# Custom {#cafe_menu-1}
+
+# Café Menu
+
+Café menu
+---
+
+## Café menu`,
+			Expected: `<h1 id="cafe_menu-1">Custom</h1>
+<h1 id="cafe_menu">Café Menu</h1>
+<h2 id="cafe_menu-2">Café menu</h2>
+<h2 id="cafe_menu-3">Café menu</h2>`,
+		},
+		t,
+	)
+}
+
+func TestEncodeDestinationSpaces(t *testing.T) {
+	markdown := New(
+		WithParserOptions(
+			parser.WithEncodeDestinationSpaces(),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "spaces in link destinations are percent-encoded",
+			Markdown:    `[x](a b) [y](my file.md "title") ![z](a b(1).png ) [w](<a b>)`,
+			Expected:    `<p><a href="a%20b">x</a> <a href="my%20file.md" title="title">y</a> <img src="a%20b(1).png" alt="z"> <a href="a%20b">w</a></p>`,
+		},
+		t,
+	)
+	testutil.DoTestCase(
+		New(),
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "spaces end link destinations by default",
+			Markdown:    `[x](a b)`,
+			Expected:    `<p>[x](a b)</p>`,
+		},
+		t,
+	)
+}
+
+func TestImageDecoding(t *testing.T) {
+	markdown := New(
+		WithRendererOptions(
+			html.WithImageDecoding("async"),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "decoding attribute is rendered",
+			Markdown:    `![alt](/image.png "title")`,
+			Expected:    `<p><img src="/image.png" alt="alt" title="title" decoding="async"></p>`,
+		},
+		t,
+	)
+
+	markdown = New()
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "decoding attribute is not rendered by default",
+			Markdown:    `![alt](/image.png)`,
+			Expected:    `<p><img src="/image.png" alt="alt"></p>`,
+		},
+		t,
+	)
+}
+
+func TestCodeSpanTrim(t *testing.T) {
+	markdown := New()
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "a leading and trailing space are stripped",
+			Markdown:    "` a `",
+			Expected:    "<p><code>a</code></p>",
+		},
+		t,
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "only a trailing space is not stripped",
+			Markdown:    "`a `",
+			Expected:    "<p><code>a </code></p>",
+		},
+		t,
+	)
+
+	markdown = New(
+		WithRendererOptions(
+			html.WithoutCodeSpanTrim(),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          3,
+			Description: "stripping is disabled",
+			Markdown:    "` a ` and `` `b` `` and `c `",
+			Expected:    "<p><code> a </code> and <code> `b` </code> and <code>c </code></p>",
+		},
+		t,
+	)
+}
+
+func TestOrderedListType(t *testing.T) {
+	markdown := New(
+		WithRendererOptions(
+			html.WithOrderedListType('a'),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "type attribute is rendered only for ordered lists",
+			Markdown:    "3. a\n4. b\n\n- c",
+			Expected: `<ol start="3" type="a">
+<li>a</li>
+<li>b</li>
+</ol>
+<ul>
+<li>c</li>
+</ul>`,
+		},
+		t,
+	)
+}
+
+func TestMergeAdjacentText(t *testing.T) {
+	markdown := New(
+		WithParserOptions(
+			parser.WithMergeAdjacentText(),
+		),
+	)
+	source := []byte("foo *bar baz\nqux_ [x]")
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	paragraph := doc.FirstChild()
+	var texts []string
+	var flags []bool
+	for c := paragraph.FirstChild(); c != nil; c = c.NextSibling() {
+		texts = append(texts, string(c.Text(source)))
+		flags = append(flags, c.(*ast.Text).SoftLineBreak())
+	}
+	if len(texts) != 2 || texts[0] != "foo *bar baz" || texts[1] != "qux_ [x]" {
+		t.Errorf("adjacent texts should be merged, but got %q", texts)
+	}
+	if len(flags) != 2 || !flags[0] || flags[1] {
+		t.Errorf("soft line breaks should be preserved, but got %v", flags)
+	}
+}
+
+func TestNewlineHardBreaks(t *testing.T) {
+	markdown := New(
+		WithParserOptions(
+			parser.WithNewlineHardBreaks(),
+		),
+	)
+	source := []byte("foo\nbar *baz*\nqux")
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	paragraph := doc.FirstChild()
+	hardLineBreaks := 0
+	for c := paragraph.FirstChild(); c != nil; c = c.NextSibling() {
+		if txt, ok := c.(*ast.Text); ok {
+			if txt.SoftLineBreak() {
+				hardLineBreaks = -1
+				break
+			}
+			if txt.HardLineBreak() {
+				hardLineBreaks++
+			}
+		}
+	}
+	if hardLineBreaks != 2 {
+		t.Errorf("newlines should be parsed as 2 hard line breaks, but got %d", hardLineBreaks)
+	}
+
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "newlines are rendered as hard line breaks",
+			Markdown:    string(source),
+			Expected: `<p>foo<br>
+bar <em>baz</em><br>
+qux</p>`,
+		},
+		t,
+	)
+}
+
+func TestParagraphHardWraps(t *testing.T) {
+	markdown := New(
+		WithRendererOptions(
+			html.WithParagraphHardWraps(),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "only softline breaks in paragraphs are rendered as hard line breaks",
+			Markdown:    "Multi\nline\n---\n\nfoo\n*bar\nbaz* `a\nb`\n\n- c\n  d\n- e\n\n```\nf\ng\n```",
+			Expected: `<h2>Multi
+line</h2>
+<p>foo<br>
+<em>bar<br>
+baz</em> <code>a b</code></p>
+<ul>
+<li>c<br>
+d</li>
+<li>e</li>
+</ul>
+<pre><code>f
+g
+</code></pre>`,
+		},
+		t,
+	)
+}
+
+func TestCJKFlanking(t *testing.T) {
+	source := "これは**重要**です。\n\nこれは**「重要」**です。\n\nこれは*「強調」*です。"
+	testutil.DoTestCase(
+		New(),
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "delimiters followed by CJK punctuations can not open emphasis by default",
+			Markdown:    source,
+			Expected: `<p>これは<strong>重要</strong>です。</p>
+<p>これは**「重要」**です。</p>
+<p>これは*「強調」*です。</p>`,
+		},
+		t,
+	)
+	testutil.DoTestCase(
+		New(
+			WithParserOptions(
+				parser.WithCJKFlanking(),
+			),
+		),
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "delimiters adjacent to CJK characters can open and close emphasis",
+			Markdown:    source,
+			Expected: `<p>これは<strong>重要</strong>です。</p>
+<p>これは<strong>「重要」</strong>です。</p>
+<p>これは<em>「強調」</em>です。</p>`,
+		},
+		t,
+	)
+}
+
+func TestURLNormalizer(t *testing.T) {
+	markdown := New(
+		WithRendererOptions(
+			html.WithURLNormalizer(func(dest []byte) ([]byte, bool) {
+				for _, c := range dest {
+					if c < 0x20 || c == 0x7f {
+						return nil, false
+					}
+				}
+				return bytes.ReplaceAll(bytes.TrimSpace(dest), []byte(" "), []byte("-")), true
+			}),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "destinations are normalized",
+			Markdown:    "[link](</my page>) ![image](</my image.png>)",
+			Expected:    `<p><a href="/my-page">link</a> <img src="/my-image.png" alt="image"></p>`,
+		},
+		t,
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "links are rendered as plain text if destinations are dropped",
+			Markdown:    "[*link*](</a\x01b>) ![image](</a\x01b>)",
+			Options:     testutil.MarkdownTestCaseOptions{EnableEscape: true},
+			Expected:    `<p><em>link</em> image</p>`,
+		},
+		t,
+	)
+}
+
+func TestImagePlaceholder(t *testing.T) {
+	markdown := New(
+		WithRendererOptions(
+			html.WithImagePlaceholder(`<span class="blocked-image"></span>`),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "placeholder is rendered for dangerous images",
+			Markdown:    "![image](data:text/html;base64,PHNjcmlwdD4=)",
+			Expected:    `<p><span class="blocked-image"></span></p>`,
+		},
+		t,
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "safe images are rendered as usual",
+			Markdown:    "![image](/image.png)",
+			Expected:    `<p><img src="/image.png" alt="image"></p>`,
+		},
+		t,
+	)
+
+	markdown = New(
+		WithRendererOptions(
+			html.WithImagePlaceholder(`<span class="blocked-image"></span>`),
+			html.WithURLNormalizer(func(dest []byte) ([]byte, bool) {
+				return dest, !bytes.HasPrefix(dest, []byte("data:"))
+			}),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          3,
+			Description: "placeholder is rendered for images rejected by the URLNormalizer",
+			Markdown:    "![image](data:image/png;base64,iVBORw0KGgo=) ![image](/image.png)",
+			Expected:    `<p><span class="blocked-image"></span> <img src="/image.png" alt="image"></p>`,
+		},
+		t,
+	)
+}
+
+func TestCodeBlockLangAttr(t *testing.T) {
+	markdown := New(
+		WithRendererOptions(
+			html.WithCodeBlockLangAttr(),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "data-lang attribute is rendered on <pre>",
+			Markdown:    "```go\nfunc main() {}\n```\n\n```\nplain\n```",
+			Expected: `<pre data-lang="go"><code class="language-go">func main() {}
+</code></pre>
+<pre><code>plain
+</code></pre>`,
+		},
+		t,
+	)
+}
+
+func titleCase(v []byte) []byte {
+	words := bytes.Split(v, []byte(" "))
+	for i, word := range words {
+		if len(word) != 0 {
+			words[i] = append(bytes.ToUpper(word[:1]), word[1:]...)
+		}
+	}
+	return bytes.Join(words, []byte(" "))
+}
+
+func TestCodeBlockInfoClass(t *testing.T) {
+	markdown := New(
+		WithRendererOptions(
+			html.WithCodeBlockInfoClass(),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "extra info words are rendered as classes",
+			Markdown:    "```go playground  <wide>\nfunc main() {}\n```",
+			Expected: `<pre><code class="language-go playground &lt;wide&gt;">func main() {}
+</code></pre>`,
+		},
+		t,
+	)
+
+	markdown = New()
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "extra info words are not rendered by default",
+			Markdown:    "```go playground\nfunc main() {}\n```",
+			Expected: `<pre><code class="language-go">func main() {}
+</code></pre>`,
+		},
+		t,
+	)
+}
+
+func TestHeadingTextTransformer(t *testing.T) {
+	markdown := New(
+		WithParserOptions(
+			parser.WithHeadingTextTransformer(titleCase),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "heading texts are title-cased",
+			Markdown:    "# hello *big* world &amp; `code span`\n\nnot a heading",
+			Expected: `<h1>Hello <em>Big</em> World &amp; <code>code span</code></h1>
+<p>not a heading</p>`,
+		},
+		t,
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "line breaks in headings are kept",
+			Markdown:    "line one\\\nline two\nline three\n===",
+			Expected: `<h1>Line One<br>
+Line Two
+Line Three</h1>`,
+		},
+		t,
+	)
+}
+
+func TestHeadingClassByLevel(t *testing.T) {
+	markdown := New(
+		WithParserOptions(
+			parser.WithAttribute(),
+		),
+		WithRendererOptions(
+			html.WithHeadingClassByLevel(map[int]string{
+				1: "h1-style",
+				2: "h2-style",
+			}),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "classes are rendered according to heading levels",
+			Markdown: `# One
+
+## Two {.custom}
+
+### Three`,
+			Expected: `<h1 class="h1-style">One</h1>
+<h2 class="custom h2-style">Two</h2>
+<h3>Three</h3>`,
+		},
+		t,
+	)
+}
+
+func TestHeadingClassByLevelWithCache(t *testing.T) {
+	markdown := New(
+		WithParserOptions(
+			parser.WithAttribute(),
+			parser.WithCache(&mapCache{entries: map[[sha256.Size]byte]*parser.CacheEntry{}}),
+		),
+		WithRendererOptions(
+			html.WithHeadingClassByLevel(map[int]string{
+				2: "h2-style",
+			}),
+		),
+	)
+	source := []byte("## Title {.x}")
+	expected := "<h2 class=\"x h2-style\">Title</h2>\n"
+	for i := 0; i < 3; i++ {
+		var b bytes.Buffer
+		if err := markdown.Convert(source, &b); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != expected {
+			t.Errorf("%d: expected %q, but got %q", i, expected, b.String())
+		}
+	}
+}
+
+func TestHeadingClassByLevelWithStringClass(t *testing.T) {
+	markdown := New(
+		WithRendererOptions(
+			html.WithHeadingClassByLevel(map[int]string{
+				1: "h1-style",
+			}),
+		),
+	)
+	source := []byte("# Title")
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	doc.FirstChild().SetAttributeString("class", "x")
+	var b bytes.Buffer
+	if err := markdown.Renderer().Render(&b, source, doc); err != nil {
+		t.Fatal(err)
+	}
+	expected := "<h1 class=\"x h1-style\">Title</h1>\n"
+	if b.String() != expected {
+		t.Errorf("expected %q, but got %q", expected, b.String())
+	}
+}
+
+func TestThematicBreakContextClass(t *testing.T) {
+	markdown := New(
+		WithRendererOptions(
+			html.WithThematicBreakContextClass(func(prev ast.Node) string {
+				if prev != nil && prev.Kind() == ast.KindList {
+					return "after-list"
+				}
+				return ""
+			}),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "class reflects the preceding node",
+			Markdown:    "- a\n- b\n\n***\n\nparagraph\n\n***",
+			Expected: `<ul>
+<li>a</li>
+<li>b</li>
+</ul>
+<hr class="after-list">
+<p>paragraph</p>
+<hr>`,
+		},
+		t,
+	)
+}
+
+func TestThematicBreakContextClassDoesNotModifyNode(t *testing.T) {
+	markdown := New(
+		WithRendererOptions(
+			html.WithThematicBreakContextClass(func(prev ast.Node) string {
+				return "after-list"
+			}),
+		),
+	)
+	source := []byte("- a\n\n***")
+	doc := markdown.Parser().Parse(text.NewReader(source))
+	doc.LastChild().SetAttributeString("class", "rule")
+	expected := "<ul>\n<li>a</li>\n</ul>\n<hr class=\"rule after-list\">\n"
+	for i := 0; i < 3; i++ {
+		var b bytes.Buffer
+		if err := markdown.Renderer().Render(&b, source, doc); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != expected {
+			t.Errorf("%d: expected %q, but got %q", i, expected, b.String())
+		}
+	}
+}
+
+func TestPullQuoteDetector(t *testing.T) {
+	markdown := New(
+		WithRendererOptions(
+			html.WithPullQuoteDetector(func(bq *ast.Blockquote) bool {
+				return bq.ChildCount() == 1 && bq.FirstChild().Lines().Len() == 1
+			}),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "detected blockquotes are rendered as pull-quotes",
+			Markdown: `> A single prominent line.
+
+> A regular
+> blockquote.`,
+			Expected: `<figure class="pullquote">
+<blockquote>
+<p>A single prominent line.</p>
+</blockquote>
+</figure>
+<blockquote>
+<p>A regular
+blockquote.</p>
+</blockquote>`,
+		},
+		t,
+	)
+}
+
+func TestDownloadLinks(t *testing.T) {
+	markdown := New(
+		WithRendererOptions(
+			html.WithDownloadLinks(func(dest []byte) bool {
+				return bytes.HasSuffix(dest, []byte(".zip")) || bytes.HasSuffix(dest, []byte(".pdf"))
+			}),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "download attributes are rendered on matched links",
+			Markdown:    `[archive](/files/a.zip "Archive") [page](/files/a.html) [manual](/files/a.pdf)`,
+			Expected:    `<p><a href="/files/a.zip" title="Archive" download="">archive</a> <a href="/files/a.html">page</a> <a href="/files/a.pdf" download="">manual</a></p>`,
+		},
+		t,
+	)
+}
+
+func TestPrintURLs(t *testing.T) {
+	markdown := New(
+		WithRendererOptions(
+			html.WithPrintURLs(func(dest []byte) bool {
+				return bytes.HasPrefix(dest, []byte("https://")) || bytes.HasPrefix(dest, []byte("javascript:"))
+			}),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "URLs are rendered after matched links",
+			Markdown:    `[external](https://example.com/?a=1&b=2 "Title") [internal](/page) [script](javascript:alert(1))`,
+			Expected:    `<p><a href="https://example.com/?a=1&amp;b=2" title="Title">external</a><span class="print-url">https://example.com/?a=1&amp;b=2</span> <a href="/page">internal</a> <a href="">script</a></p>`,
+		},
+		t,
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "URLs are rendered after autolinks",
+			Markdown:    `<https://example.com/a?b=1&c=2> <http://example.com/> <foo@example.com>`,
+			Expected:    `<p><a href="https://example.com/a?b=1&amp;c=2">https://example.com/a?b=1&amp;c=2</a><span class="print-url">https://example.com/a?b=1&amp;c=2</span> <a href="http://example.com/">http://example.com/</a> <a href="mailto:foo@example.com">foo@example.com</a></p>`,
+		},
+		t,
+	)
+}
+
+func TestAutoLinkBreakHints(t *testing.T) {
+	markdown := New(
+		WithRendererOptions(
+			html.WithAutoLinkBreakHints(),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Break hints are inserted into autolink labels",
+			Markdown:    `<https://example.com/a?b=1&c=2> [https://example.com/a](https://example.com/a)`,
+			Expected:    `<p><a href="https://example.com/a?b=1&amp;c=2">https://<wbr>example.com/<wbr>a?<wbr>b=1&amp;<wbr>c=2</a> <a href="https://example.com/a">https://example.com/a</a></p>`,
+		},
+		t,
+	)
+}
+
+func TestFullDocument(t *testing.T) {
+	markdown := New(
+		WithRendererOptions(
+			html.WithFullDocument(html.DocumentOptions{
+				Lang:        "en",
+				Stylesheets: []string{"/style.css", "print.css?media=print&v=1"},
+			}),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "The first heading is used as a title",
+			Markdown: `text
+
+# Hello *world* & 1 < 2`,
+			Expected: `<!doctype html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Hello world &amp; 1 &lt; 2</title>
+<link rel="stylesheet" href="/style.css">
+<link rel="stylesheet" href="print.css?media=print&amp;v=1">
+</head>
+<body>
+<p>text</p>
+<h1>Hello <em>world</em> &amp; 1 &lt; 2</h1>
+</body>
+</html>`,
+		},
+		t,
+	)
+
+	markdown = New(
+		WithRendererOptions(
+			html.WithXHTML(),
+			html.WithFullDocument(html.DocumentOptions{
+				Title: "A & B",
+			}),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "The given title is used",
+			Markdown:    `# Heading`,
+			Expected: `<!doctype html>
+<html>
+<head>
+<meta charset="utf-8" />
+<title>A &amp; B</title>
+</head>
+<body>
+<h1>Heading</h1>
+</body>
+</html>`,
+		},
+		t,
+	)
+
+	markdown = New(
+		WithRendererOptions(
+			html.WithFullDocument(html.DocumentOptions{}),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          3,
+			Description: "References and escapes in the heading are escaped once",
+			Markdown:    `## a &amp; b \<d\> ` + "`<e>`",
+			Expected: `<!doctype html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>a &amp; b &lt;d&gt; &lt;e&gt;</title>
+</head>
+<body>
+<h2>a &amp; b &lt;d&gt; <code>&lt;e&gt;</code></h2>
+</body>
+</html>`,
+		},
+		t,
+	)
+}
+
+func TestCodeTokenizer(t *testing.T) {
+	markdown := New(
+		WithRendererOptions(
+			html.WithCodeTokenizer(func(lang, code []byte) []html.Token {
+				if string(lang) != "go" {
+					return nil
+				}
+				var tokens []html.Token
+				for _, word := range strings.SplitAfter(string(code), " ") {
+					class := ""
+					if strings.TrimSpace(word) == "func" {
+						class = "keyword"
+					}
+					tokens = append(tokens, html.Token{Class: class, Text: []byte(word)})
+				}
+				return tokens
+			}),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "tokens are rendered as spans",
+			Markdown:    "```go\nfunc a() <b>\n```\n\n```text\nfunc a()\n```\n\n    func a()\n\n`func a()`\n",
+			Expected: `<pre><code class="language-go"><span class="tok-keyword">func </span>a() &lt;b&gt;
+</code></pre>
+<pre><code class="language-text">func a()
+</code></pre>
+<pre><code>func a()
+</code></pre>
+<p><code>func a()</code></p>`,
+		},
+		t,
+	)
+}
+
+func TestMaxNodes(t *testing.T) {
+	var source bytes.Buffer
+	source.WriteString("# Title\n\n")
+	for i := 0; i < 1000; i++ {
+		source.WriteString("paragraph *emphasis*\n\n")
+	}
+	markdown := New(
+		WithRendererOptions(
+			renderer.WithMaxNodes(8),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "output is truncated after the given number of nodes",
+			Markdown:    source.String(),
+			Expected: `<h1>Title</h1>
+<p>paragraph <em>emphasis</em></p>
+<p>paragraph </p>
+…`,
+		},
+		t,
+	)
+
+	markdown = New(
+		WithRendererOptions(
+			renderer.WithMaxNodes(4),
+			renderer.WithTruncationNotice(`<p class="truncated">(truncated)</p>`),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "truncation notice can be changed",
+			Markdown:    source.String(),
+			Expected: `<h1>Title</h1>
+<p>paragraph </p>
+<p class="truncated">(truncated)</p>`,
+		},
+		t,
+	)
+
+	markdown = New(
+		WithRendererOptions(
+			renderer.WithMaxNodes(100),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          3,
+			Description: "output is not truncated within the limit",
+			Markdown:    "# Title\n\nparagraph",
+			Expected: `<h1>Title</h1>
+<p>paragraph</p>`,
+		},
+		t,
+	)
+}
+
+func TestMaxNestingDepth(t *testing.T) {
+	markdown := New(
+		WithParserOptions(
+			parser.WithMaxNestingDepth(2),
+		),
+	)
+	cases := []testutil.MarkdownTestCase{
+		{
+			No:          1,
+			Description: "blockquotes nested deeper than the limit are parsed as texts",
+			Markdown:    ">>>> a",
+			Expected: `<blockquote>
+<blockquote>
+<p>&gt;&gt; a</p>
+</blockquote>
+</blockquote>`,
+		},
+		{
+			No:          2,
+			Description: "a list and its items are counted as one level",
+			Markdown: `- a
+  > - b
+  >   - c`,
+			Expected: `<ul>
+<li>a
+<blockquote>
+<p>- b
+- c</p>
+</blockquote>
+</li>
+</ul>`,
+		},
+		{
+			No:          3,
+			Description: "emphasis delimiters nested deeper than the limit are parsed as texts",
+			Markdown:    "***a*** *b **c *d* c** b* *e* *f*",
+			Expected:    "<p><em><strong>a</strong></em> *b <strong>c <em>d</em> c</strong> b* <em>e</em> <em>f</em></p>",
+		},
+		{
+			No:          4,
+			Description: "links nested deeper than the limit are parsed as texts",
+			Markdown:    "[a] [b] [c](/c) [d ![e ![f](/f)](/e)](/d)",
+			Expected:    `<p>[a] [b] <a href="/c">c</a> [d <img src="/e" alt="e f">](/d)</p>`,
+		},
+		{
+			No:          5,
+			Description: "delimiters and brackets that are never matched are not counted",
+			Markdown:    "a *b *c *d* [[[e]]](/e)",
+			Expected:    `<p>a *b *c <em>d</em> <a href="/e">[[e]]</a></p>`,
+		},
+	}
+	for _, c := range cases {
+		testutil.DoTestCase(markdown, c, t)
+	}
+
+	markdown = New(
+		WithParserOptions(
+			parser.WithMaxNestingDepth(100),
+		),
+	)
+	for _, source := range []string{
+		strings.Repeat(">", 100000) + " a",
+		strings.Repeat("- ", 100000) + "a",
+		strings.Repeat("[", 100000) + "a" + strings.Repeat("](/u)", 100000),
+		strings.Repeat("*", 100000) + "a" + strings.Repeat("*", 100000),
+	} {
+		var b bytes.Buffer
+		if err := markdown.Convert([]byte(source), &b); err != nil {
+			t.Fatal(err)
+		}
+		doc := markdown.Parser().Parse(text.NewReader([]byte(source)))
+		// a document, a paragraph and a text are added to 100 levels.
+		if depth := maxDepth(doc); depth > 100+3 {
+			t.Errorf("AST must be nested at most 100 levels, but got %d levels", depth)
+		}
+	}
+}
+
+func TestMaxEmphasisNesting(t *testing.T) {
+	markdown := New(
+		WithParserOptions(
+			parser.WithMaxEmphasisNesting(2),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "emphasis delimiters nested deeper than the limit are parsed as texts",
+			Markdown:    "*a _b *c* b_ a* a *b *c *d* [[[d]]](/d)\n>>> e",
+			Expected: "<p>*a <em>b <em>c</em> b</em> a* a *b *c <em>d</em> <a href=\"/d\">[[d]]</a></p>\n" +
+				"<blockquote>\n<blockquote>\n<blockquote>\n<p>e</p>\n</blockquote>\n</blockquote>\n</blockquote>",
+		},
+		t,
+	)
+
+	source := strings.Repeat("*_", 100000) + "a" + strings.Repeat("_*", 100000)
+	doc := markdown.Parser().Parse(text.NewReader([]byte(source)))
+	// a document, a paragraph and a text are added to 2 levels.
+	if depth := maxDepth(doc); depth > 2+3 {
+		t.Errorf("emphasis must be nested at most 2 levels, but got %d levels", depth)
+	}
+}
+
+func maxDepth(n ast.Node) int {
+	depth := 0
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if d := maxDepth(c); d > depth {
+			depth = d
+		}
+	}
+	if n.Kind() == ast.KindListItem {
+		return depth
+	}
+	return depth + 1
+}
+
+func TestInlineCodeBlocks(t *testing.T) {
+	markdown := New(
+		WithRendererOptions(
+			html.WithInlineCodeBlocks(),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "fenced code blocks with the {inline} flag are rendered without pre",
+			Markdown:    "```go {inline}\nfmt.Println(\"hello\")\n```\n\n```{inline}\nplain\n```\n\n```go\nfmt.Println(\"hello\")\n```",
+			Expected: `<code class="language-go">fmt.Println(&quot;hello&quot;)
+</code>
+<code>plain
+</code>
+<pre><code class="language-go">fmt.Println(&quot;hello&quot;)
+</code></pre>`,
+		},
+		t,
+	)
+
+	markdown = New()
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "the {inline} flag is not handled by default",
+			Markdown:    "```go {inline}\nfmt.Println(\"hello\")\n```\n\n```{inline}\nplain\n```",
+			Expected: `<pre><code class="language-go">fmt.Println(&quot;hello&quot;)
+</code></pre>
+<pre><code class="language-{inline}">plain
+</code></pre>`,
+		},
+		t,
+	)
+}
+
+func TestNestedOrderedNumbering(t *testing.T) {
+	markdown := New(
+		WithRendererOptions(
+			html.WithNestedOrderedNumbering(),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "hierarchical numbers are rendered",
+			Markdown: `1. one
+   1. one-one
+   2. one-two
+      - bullet
+2. two`,
+			Expected: `<ol>
+<li data-number="1.">one
+<ol>
+<li data-number="1.1">one-one</li>
+<li data-number="1.2">one-two
+<ul>
+<li>bullet</li>
+</ul>
+</li>
+</ol>
+</li>
+<li data-number="2.">two</li>
+</ol>`,
+		},
+		t,
+	)
+}
+
+func TestCollapsibleCodeBlocks(t *testing.T) {
+	markdown := New(
+		WithRendererOptions(
+			html.WithCollapsibleCodeBlocks(2),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "short code blocks are rendered normally",
+			Markdown:    "```go\na\nb\n```\n\n    c\n    d\n",
+			Expected: `<pre><code class="language-go">a
+b
+</code></pre>
+<pre><code>c
+d
+</code></pre>`,
+		},
+		t,
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "long code blocks are wrapped in details",
+			Markdown:    "```go\na\nb\nc\n```\n\n    d\n    e\n    f\n",
+			Expected: `<details>
+<summary>Show 3 lines</summary>
+<pre><code class="language-go">a
+b
+c
+</code></pre>
+</details>
+<details>
+<summary>Show 3 lines</summary>
+<pre><code>d
+e
+f
+</code></pre>
+</details>`,
+		},
+		t,
+	)
+}
+
+func TestCollapsibleLists(t *testing.T) {
+	markdown := New(
+		WithRendererOptions(
+			html.WithCollapsibleLists(2),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "short lists are rendered normally",
+			Markdown:    "- a\n- b\n\n1. c\n",
+			Expected: `<ul>
+<li>a</li>
+<li>b</li>
+</ul>
+<ol>
+<li>c</li>
+</ol>`,
+		},
+		t,
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "remaining items of long lists are wrapped in details",
+			Markdown:    "- a\n- b\n- c\n\n3. d\n4. e\n5. f\n   - g\n   - h\n   - i\n6. j\n",
+			Expected: `<ul>
+<li>a</li>
+<li>b</li>
+</ul>
+<details>
+<summary>Show 1 more item</summary>
+<ul>
+<li>c</li>
+</ul>
+</details>
+<ol start="3">
+<li>d</li>
+<li>e</li>
+</ol>
+<details>
+<summary>Show 2 more items</summary>
+<ol start="5">
+<li>f
+<ul>
+<li>g</li>
+<li>h</li>
+</ul>
+<details>
+<summary>Show 1 more item</summary>
+<ul>
+<li>i</li>
+</ul>
+</details>
+</li>
+<li>j</li>
+</ol>
+</details>`,
+		},
+		t,
+	)
+}
+
+func TestUnsafeRawHTMLIsNotEscaped(t *testing.T) {
+	markdown := New(
+		WithRendererOptions(
+			html.WithUnsafe(),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "entity references in inline raw HTML are not escaped again",
+			Markdown:    `x <a href="?a=1&amp;b=2" title='&lt;&quot;&gt;'>link</a>`,
+			Expected:    `<p>x <a href="?a=1&amp;b=2" title='&lt;&quot;&gt;'>link</a></p>`,
+		},
+		t,
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "entity references in HTML blocks are not escaped again",
+			Markdown: `<div title="a &amp; b">
+<a href="?a=1&amp;b=2">link</a>
+</div>`,
+			Expected: `<div title="a &amp; b">
+<a href="?a=1&amp;b=2">link</a>
+</div>`,
+		},
+		t,
+	)
+}
@@ -82,6 +82,42 @@ func ParseAttributes(reader text.Reader) (Attributes, bool) {
 	}
 }
 
+// parseTrailingAttributes parses attributes like '{#id .class}' at the end of
+// the given line. parseTrailingAttributes returns the attributes and the
+// position of the '{' if the line ends with attributes, otherwise nil and -1.
+func parseTrailingAttributes(line []byte) (Attributes, int) {
+	lr := text.NewReader(line)
+	var attrs Attributes
+	var ok bool
+	var start text.Segment
+	var sl int
+	var end text.Segment
+	for {
+		c := lr.Peek()
+		if c == text.EOF {
+			break
+		}
+		if c == '\\' {
+			lr.Advance(1)
+			if lr.Peek() == '{' {
+				lr.Advance(1)
+			}
+			continue
+		}
+		if c == '{' {
+			sl, start = lr.Position()
+			attrs, ok = ParseAttributes(lr)
+			_, end = lr.Position()
+			lr.SetPosition(sl, start)
+		}
+		lr.Advance(1)
+	}
+	if ok && util.IsBlank(line[end.Start:]) {
+		return attrs, start.Start
+	}
+	return nil, -1
+}
+
 func parseAttribute(reader text.Reader) (Attribute, bool) {
 	reader.SkipSpaces()
 	c := reader.Peek()
@@ -10,6 +10,10 @@ import (
 type HeadingConfig struct {
 	AutoHeadingID bool
 	Attribute     bool
+
+	// IDFunc generates slugs of auto heading ids from heading texts.
+	// If IDFunc is nil, IDs.Generate is used.
+	IDFunc func(value []byte) []byte
 }
 
 // SetOption implements SetOptioner.
@@ -19,6 +23,8 @@ func (b *HeadingConfig) SetOption(name OptionName, value interface{}) {
 		b.AutoHeadingID = true
 	case optAttribute:
 		b.Attribute = true
+	case optHeadingIDFunc:
+		b.IDFunc = value.(func([]byte) []byte)
 	}
 }
 
@@ -48,6 +54,29 @@ func WithAutoHeadingID() HeadingOption {
 	return &withAutoHeadingID{}
 }
 
+// HeadingIDFunc is an option name used in WithHeadingIDFunc.
+const optHeadingIDFunc OptionName = "HeadingIDFunc"
+
+type withHeadingIDFunc struct {
+	value func([]byte) []byte
+}
+
+func (o *withHeadingIDFunc) SetParserOption(c *Config) {
+	c.Options[optHeadingIDFunc] = o.value
+}
+
+func (o *withHeadingIDFunc) SetHeadingOption(p *HeadingConfig) {
+	p.IDFunc = o.value
+}
+
+// WithHeadingIDFunc is a functional option that generates auto heading ids
+// with the given function. The function receives a text of the heading and
+// returns a slug. Duplicated slugs are suffixed with '-1', '-2' and so on.
+// This option works with WithAutoHeadingID.
+func WithHeadingIDFunc(f func(value []byte) []byte) HeadingOption {
+	return &withHeadingIDFunc{f}
+}
+
 type withHeadingAttribute struct {
 	Option
 }
@@ -61,6 +90,69 @@ func WithHeadingAttribute() HeadingOption {
 	return &withHeadingAttribute{WithAttribute()}
 }
 
+type headingTextTransformer struct {
+	transform func([]byte) []byte
+}
+
+func (t *headingTextTransformer) Transform(node *ast.Document, reader text.Reader, pc Context) {
+	source := reader.Source()
+	var texts []*ast.Text
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n.Kind() {
+		case ast.KindCodeSpan:
+			return ast.WalkSkipChildren, nil
+		case ast.KindText:
+			for p := n.Parent(); p != nil; p = p.Parent() {
+				if p.Kind() == ast.KindHeading {
+					texts = append(texts, n.(*ast.Text))
+					break
+				}
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	for _, n := range texts {
+		value := n.Segment.Value(source)
+		if !n.IsRaw() {
+			value = util.UnescapePunctuations(value)
+			value = util.ResolveNumericReferences(value)
+			value = util.ResolveEntityNames(value)
+		}
+		value = t.transform(value)
+		s := ast.NewString(value)
+		s.SetRaw(true)
+		parent := n.Parent()
+		parent.ReplaceChild(parent, n, s)
+		if n.SoftLineBreak() || n.HardLineBreak() {
+			// line breaks are kept as an empty text that has the same flags.
+			br := ast.NewTextSegment(text.NewSegment(n.Segment.Stop, n.Segment.Stop))
+			br.SetSoftLineBreak(n.SoftLineBreak())
+			br.SetHardLineBreak(n.HardLineBreak())
+			parent.InsertAfter(parent, s, br)
+		}
+	}
+}
+
+type withHeadingTextTransformer struct {
+	value func([]byte) []byte
+}
+
+func (o *withHeadingTextTransformer) SetParserOption(c *Config) {
+	c.ASTTransformers = append(c.ASTTransformers,
+		util.Prioritized(&headingTextTransformer{o.value}, 100))
+}
+
+// WithHeadingTextTransformer is a functional option that transforms texts of
+// all headings with the given function. The function receives texts whose
+// backslash escapes and character references are resolved.
+// Code spans in headings are not transformed.
+func WithHeadingTextTransformer(f func(text []byte) []byte) Option {
+	return &withHeadingTextTransformer{f}
+}
+
 type atxHeadingParser struct {
 	HeadingConfig
 }
@@ -177,7 +269,7 @@ func (b *atxHeadingParser) Close(node ast.Node, reader text.Reader, pc Context)
 	if b.AutoHeadingID {
 		id, ok := node.AttributeString("id")
 		if !ok {
-			generateAutoHeadingID(node.(*ast.Heading), reader, pc)
+			generateAutoHeadingID(node.(*ast.Heading), reader, pc, b.IDFunc)
 		} else {
 			pc.IDs().Put(id.([]byte))
 		}
@@ -192,14 +284,23 @@ func (b *atxHeadingParser) CanAcceptIndentedLine() bool {
 	return false
 }
 
-func generateAutoHeadingID(node *ast.Heading, reader text.Reader, pc Context) {
+func generateAutoHeadingID(node *ast.Heading, reader text.Reader, pc Context, idFunc func([]byte) []byte) {
 	var line []byte
 	lastIndex := node.Lines().Len() - 1
 	if lastIndex > -1 {
 		lastLine := node.Lines().At(lastIndex)
 		line = lastLine.Value(reader.Source())
 	}
-	headingID := pc.IDs().Generate(line, ast.KindHeading)
+	var headingID []byte
+	if idFunc == nil {
+		headingID = pc.IDs().Generate(line, ast.KindHeading)
+	} else if ids, ok := pc.IDs().(*ids); ok {
+		headingID = ids.unique(idFunc(util.TrimRightSpace(util.TrimLeftSpace(line))))
+	} else {
+		// custom IDs are responsible for uniqueness of ids.
+		headingID = idFunc(util.TrimRightSpace(util.TrimLeftSpace(line)))
+		pc.IDs().Put(headingID)
+	}
 	node.SetAttribute(attrNameID, headingID)
 }
 
@@ -209,38 +310,13 @@ func parseLastLineAttributes(node ast.Node, reader text.Reader, pc Context) {
 		return
 	}
 	lastLine := node.Lines().At(lastIndex)
-	line := lastLine.Value(reader.Source())
-	lr := text.NewReader(line)
-	var attrs Attributes
-	var ok bool
-	var start text.Segment
-	var sl int
-	var end text.Segment
-	for {
-		c := lr.Peek()
-		if c == text.EOF {
-			break
-		}
-		if c == '\\' {
-			lr.Advance(1)
-			if lr.Peek() == '{' {
-				lr.Advance(1)
-			}
-			continue
-		}
-		if c == '{' {
-			sl, start = lr.Position()
-			attrs, ok = ParseAttributes(lr)
-			_, end = lr.Position()
-			lr.SetPosition(sl, start)
-		}
-		lr.Advance(1)
+	attrs, start := parseTrailingAttributes(lastLine.Value(reader.Source()))
+	if start < 0 {
+		return
 	}
-	if ok && util.IsBlank(line[end.Start:]) {
-		for _, attr := range attrs {
-			node.SetAttribute(attr.Name, attr.Value)
-		}
-		lastLine.Stop = lastLine.Start + start.Start
-		node.Lines().Set(lastIndex, lastLine)
+	for _, attr := range attrs {
+		node.SetAttribute(attr.Name, attr.Value)
 	}
+	lastLine.Stop = lastLine.Start + start
+	node.Lines().Set(lastIndex, lastLine)
 }
@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+type blockAttributeParser struct {
+	paragraphParser
+	Attribute bool
+}
+
+// NewBlockAttributeParser returns a new BlockParser that parses attribute
+// lines like '{#id .class}' just after fenced code blocks, lists
+// and blockquotes. Attributes are set to the preceding blocks.
+// Attribute lines after lists and blockquotes that end with paragraphs are
+// lazy continuation lines of the paragraphs.
+// This parser works only if the parser.WithAttribute option is enabled.
+func NewBlockAttributeParser() BlockParser {
+	return &blockAttributeParser{}
+}
+
+func (b *blockAttributeParser) SetOption(name OptionName, value interface{}) {
+	switch name {
+	case optAttribute:
+		b.Attribute = true
+	}
+}
+
+func (b *blockAttributeParser) Trigger() []byte {
+	return []byte{'{'}
+}
+
+// canHaveBlockAttributes returns true if the given node can have attributes
+// from attribute lines.
+// Attribute lines just after paragraphs are continuation lines of the
+// paragraphs, so they are parsed with the paragraphs.
+func canHaveBlockAttributes(node ast.Node) bool {
+	if node == nil {
+		return false
+	}
+	switch node.Kind() {
+	case ast.KindFencedCodeBlock, ast.KindList, ast.KindBlockquote:
+		return true
+	}
+	return false
+}
+
+// isBlockAttributeLine returns true if the given node is an attribute line
+// just after a block that can have attributes.
+func isBlockAttributeLine(node ast.Node) bool {
+	return node.Lines().Len() == 1 && !node.HasBlankPreviousLines() && canHaveBlockAttributes(node.PreviousSibling())
+}
+
+func (b *blockAttributeParser) Open(parent ast.Node, reader text.Reader, pc Context) (ast.Node, State) {
+	if !b.Attribute || !canHaveBlockAttributes(parent.LastChild()) {
+		return nil, NoChildren
+	}
+	// lines after paragraphs in lists and blockquotes are lazy
+	// continuation lines of the paragraphs.
+	if last := pc.LastOpenedBlock(); last.Node != nil && ast.IsParagraph(last.Node) {
+		return nil, NoChildren
+	}
+	line, segment := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || pc.BlockIndent() > 3 {
+		return nil, NoChildren
+	}
+	if attrs, start := parseTrailingAttributes(line); start != pos || len(attrs) == 0 {
+		return nil, NoChildren
+	}
+	// attribute lines after blank lines are paragraphs.
+	node := ast.NewParagraph()
+	node.Lines().Append(segment.WithStart(segment.Start + pos))
+	reader.Advance(segment.Len() - 1)
+	return node, NoChildren
+}
+
+func (b *blockAttributeParser) Continue(node ast.Node, reader text.Reader, pc Context) State {
+	if isBlockAttributeLine(node) {
+		return Close
+	}
+	return b.paragraphParser.Continue(node, reader, pc)
+}
+
+func (b *blockAttributeParser) Close(node ast.Node, reader text.Reader, pc Context) {
+	if !isBlockAttributeLine(node) {
+		b.paragraphParser.Close(node, reader, pc)
+		return
+	}
+	line := node.Lines().At(0)
+	attrs, _ := parseTrailingAttributes(line.Value(reader.Source()))
+	target := node.PreviousSibling()
+	for _, attr := range attrs {
+		if v, ok := target.Attribute(attrNameClass); ok && bytes.Equal(attr.Name, attrNameClass) {
+			// classes are appended to classes of info strings.
+			if class, ok := v.([]byte); ok {
+				attr.Value = append(append(append([]byte{}, class...), ' '), attr.Value.([]byte)...)
+			}
+		}
+		target.SetAttribute(attr.Name, attr.Value)
+	}
+	node.Parent().RemoveChild(node.Parent(), node)
+}
+
+func (b *blockAttributeParser) CanInterruptParagraph() bool {
+	return true
+}
@@ -112,6 +112,14 @@ func NewDelimiter(canOpen, canClose bool, length int, char byte, processor Delim
 
 // ScanDelimiter scans a delimiter by given DelimiterProcessor.
 func ScanDelimiter(line []byte, before rune, min int, processor DelimiterProcessor) *Delimiter {
+	return scanDelimiter(line, before, min, processor, false)
+}
+
+// scanDelimiter scans a delimiter by given DelimiterProcessor.
+// If cjk is true, east asian wide characters around the delimiter
+// are treated like whitespaces, because CJK texts do not have spaces
+// between words.
+func scanDelimiter(line []byte, before rune, min int, processor DelimiterProcessor, cjk bool) *Delimiter {
 	i := 0
 	c := line[i]
 	j := i
@@ -132,14 +140,17 @@ func ScanDelimiter(line []byte, before rune, min int, processor DelimiterProcess
 		afterIsPunctuation := util.IsPunctRune(after)
 		afterIsWhitespace := util.IsSpaceRune(after)
 
+		beforeIsCJK := cjk && util.IsEastAsianWideRune(before)
+		afterIsCJK := cjk && util.IsEastAsianWideRune(after)
+
 		isLeft := !afterIsWhitespace &&
-			(!afterIsPunctuation || beforeIsWhitespace || beforeIsPunctuation)
+			(!afterIsPunctuation || beforeIsWhitespace || beforeIsPunctuation || beforeIsCJK)
 		isRight := !beforeIsWhitespace &&
-			(!beforeIsPunctuation || afterIsWhitespace || afterIsPunctuation)
+			(!beforeIsPunctuation || afterIsWhitespace || afterIsPunctuation || afterIsCJK)
 
 		if line[i] == '_' {
-			canOpen = isLeft && (!isRight || beforeIsPunctuation)
-			canClose = isRight && (!isLeft || afterIsPunctuation)
+			canOpen = isLeft && (!isRight || beforeIsPunctuation || beforeIsCJK)
+			canClose = isRight && (!isLeft || afterIsPunctuation || afterIsCJK)
 		} else {
 			canOpen = isLeft
 			canClose = isRight
@@ -176,6 +187,7 @@ func ProcessDelimiters(bottom ast.Node, pc Context) {
 		pc.ClearDelimiters(bottom)
 		return
 	}
+	nesting := getInlineNesting(pc)
 	for closer != nil {
 		if !closer.CanClose {
 			closer = closer.NextDelimiter
@@ -203,10 +215,27 @@ func ProcessDelimiters(bottom ast.Node, pc Context) {
 			closer = next
 			continue
 		}
+		depth := 0
+		if nesting != nil && nesting.emphasisLimit > 0 {
+			depth = nesting.maxDepth(opener.NextSibling(), closer, nesting.emphasisDepths) + 1
+			if depth > nesting.emphasisLimit {
+				// pairs nested too deeply are parsed as texts.
+				next := closer.NextDelimiter
+				pc.RemoveDelimiter(opener)
+				if !closer.CanOpen {
+					pc.RemoveDelimiter(closer)
+				}
+				closer = next
+				continue
+			}
+		}
 		opener.ConsumeCharacters(consume)
 		closer.ConsumeCharacters(consume)
 
 		node := opener.Processor.OnMatch(consume)
+		if depth > 0 {
+			nesting.emphasisDepths[node] = depth
+		}
 
 		parent := opener.Parent()
 		child := opener.NextSibling()
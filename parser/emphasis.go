@@ -23,13 +23,19 @@ func (p *emphasisDelimiterProcessor) OnMatch(consumes int) ast.Node {
 var defaultEmphasisDelimiterProcessor = &emphasisDelimiterProcessor{}
 
 type emphasisParser struct {
+	CJKFlanking bool
 }
 
-var defaultEmphasisParser = &emphasisParser{}
-
 // NewEmphasisParser return a new InlineParser that parses emphasises.
 func NewEmphasisParser() InlineParser {
-	return defaultEmphasisParser
+	return &emphasisParser{}
+}
+
+func (s *emphasisParser) SetOption(name OptionName, value interface{}) {
+	switch name {
+	case optCJKFlanking:
+		s.CJKFlanking = true
+	}
 }
 
 func (s *emphasisParser) Trigger() []byte {
@@ -39,7 +45,7 @@ func (s *emphasisParser) Trigger() []byte {
 func (s *emphasisParser) Parse(parent ast.Node, block text.Reader, pc Context) ast.Node {
 	before := block.PrecendingCharacter()
 	line, segment := block.PeekLine()
-	node := ScanDelimiter(line, before, 1, defaultEmphasisDelimiterProcessor)
+	node := scanDelimiter(line, before, 1, defaultEmphasisDelimiterProcessor, s.CJKFlanking)
 	if node == nil {
 		return nil
 	}
@@ -9,14 +9,20 @@ import (
 )
 
 type fencedCodeBlockParser struct {
+	Attribute bool
 }
 
-var defaultFencedCodeBlockParser = &fencedCodeBlockParser{}
-
 // NewFencedCodeBlockParser returns a new BlockParser that
 // parses fenced code blocks.
 func NewFencedCodeBlockParser() BlockParser {
-	return defaultFencedCodeBlockParser
+	return &fencedCodeBlockParser{}
+}
+
+func (b *fencedCodeBlockParser) SetOption(name OptionName, value interface{}) {
+	switch name {
+	case optAttribute:
+		b.Attribute = true
+	}
 }
 
 type fenceData struct {
@@ -48,6 +54,7 @@ func (b *fencedCodeBlockParser) Open(parent ast.Node, reader text.Reader, pc Con
 		return nil, NoChildren
 	}
 	var info *ast.Text
+	var attrs Attributes
 	if i < len(line)-1 {
 		rest := line[i:]
 		left := util.TrimLeftSpaceLength(rest)
@@ -57,12 +64,23 @@ func (b *fencedCodeBlockParser) Open(parent ast.Node, reader text.Reader, pc Con
 			value := rest[left : len(rest)-right]
 			if fenceChar == '`' && bytes.IndexByte(value, '`') > -1 {
 				return nil, NoChildren
-			} else if infoStart != infoStop {
+			}
+			if b.Attribute {
+				// handles attributes at the end of info strings like '```go {.class}'
+				var start int
+				if attrs, start = parseTrailingAttributes(value); start > -1 {
+					infoStop = infoStart + start - util.TrimRightSpaceLength(value[:start])
+				}
+			}
+			if infoStart != infoStop {
 				info = ast.NewTextSegment(text.NewSegment(infoStart, infoStop))
 			}
 		}
 	}
 	node := ast.NewFencedCodeBlock(info)
+	for _, attr := range attrs {
+		node.SetAttribute(attr.Name, attr.Value)
+	}
 	pc.Set(fencedCodeBlockInfoKey, &fenceData{fenceChar, findent, oFenceLength, node})
 	return node, NoChildren
 
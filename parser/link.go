@@ -106,13 +106,19 @@ func removeLinkLabelState(pc Context, d *linkLabelState) {
 }
 
 type linkParser struct {
+	EncodeDestinationSpaces bool
 }
 
-var defaultLinkParser = &linkParser{}
-
 // NewLinkParser return a new InlineParser that parses links.
 func NewLinkParser() InlineParser {
-	return defaultLinkParser
+	return &linkParser{}
+}
+
+func (s *linkParser) SetOption(name OptionName, value interface{}) {
+	switch name {
+	case optEncodeDestinationSpaces:
+		s.EncodeDestinationSpaces = true
+	}
 }
 
 func (s *linkParser) Trigger() []byte {
@@ -159,6 +165,16 @@ func (s *linkParser) Parse(parent ast.Node, block text.Reader, pc Context) ast.N
 		return nil
 	}
 
+	depth := 0
+	nesting := getInlineNesting(pc)
+	if nesting != nil && nesting.linkLimit > 0 {
+		depth = nesting.maxDepth(last.NextSibling(), nil, nesting.linkDepths) + 1
+		if depth > nesting.linkLimit { // links nested too deeply are parsed as texts
+			ast.MergeOrReplaceTextSegment(last.Parent(), last, last.Segment)
+			return nil
+		}
+	}
+
 	c := block.Peek()
 	l, pos := block.Position()
 	var link *ast.Link
@@ -195,12 +211,15 @@ func (s *linkParser) Parse(parent ast.Node, block text.Reader, pc Context) ast.N
 		link.Title = ref.Title()
 		link.Destination = ref.Destination()
 	}
+	last.Parent().RemoveChild(last.Parent(), last)
+	var node ast.Node = link
 	if last.IsImage {
-		last.Parent().RemoveChild(last.Parent(), last)
-		return ast.NewImage(link)
+		node = ast.NewImage(link)
 	}
-	last.Parent().RemoveChild(last.Parent(), last)
-	return link
+	if depth > 0 {
+		nesting.linkDepths[node] = depth
+	}
+	return node
 }
 
 func (s *linkParser) containsLink(n ast.Node) bool {
@@ -299,7 +318,11 @@ func (s *linkParser) parseLink(parent ast.Node, last *linkLabelState, block text
 	if block.Peek() == ')' { // empty link like '[link]()'
 		block.Advance(1)
 	} else {
-		destination, ok = parseLinkDestination(block)
+		if s.EncodeDestinationSpaces {
+			destination, ok = parseLinkDestinationWithSpaces(block)
+		} else {
+			destination, ok = parseLinkDestination(block)
+		}
 		if !ok {
 			return nil
 		}
@@ -368,6 +391,61 @@ func parseLinkDestination(block text.Reader) ([]byte, bool) {
 	return line[:i], len(line[:i]) != 0
 }
 
+var encodedSpace = []byte("%20")
+
+// parseLinkDestinationWithSpaces parses a link destination that may contain
+// unescaped spaces like '(a b)'. Spaces are percent-encoded.
+// Spaces followed by a link title or ')' end the destination.
+func parseLinkDestinationWithSpaces(block text.Reader) ([]byte, bool) {
+	block.SkipSpaces()
+	if block.Peek() == '<' {
+		return parseLinkDestination(block)
+	}
+	line, _ := block.PeekLine()
+	opened := 0
+	i := 0
+	hasSpace := false
+	for i < len(line) {
+		c := line[i]
+		if c == '\\' && i < len(line)-1 && util.IsPunct(line[i+1]) {
+			i += 2
+			continue
+		} else if c == '(' {
+			opened++
+		} else if c == ')' {
+			opened--
+			if opened < 0 {
+				break
+			}
+		} else if c == ' ' {
+			j := i + util.TrimLeftSpaceLength(line[i:])
+			if j == len(line) {
+				break
+			}
+			if n := line[j]; n == '"' || n == '\'' || n == '(' || n == ')' {
+				break
+			}
+			hasSpace = true
+		} else if util.IsSpace(c) {
+			break
+		}
+		i++
+	}
+	block.Advance(i)
+	if !hasSpace {
+		return line[:i], i != 0
+	}
+	destination := make([]byte, 0, i+8)
+	for _, c := range line[:i] {
+		if c == ' ' {
+			destination = append(destination, encodedSpace...)
+		} else {
+			destination = append(destination, c)
+		}
+	}
+	return destination, true
+}
+
 func parseLinkTitle(block text.Reader) ([]byte, bool) {
 	block.SkipSpaces()
 	opener := block.Peek()
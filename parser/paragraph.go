@@ -63,6 +63,35 @@ func (b *paragraphParser) Close(node ast.Node, reader text.Reader, pc Context) {
 	}
 }
 
+// parseParagraphAttributes parses attributes like '{#id .class}' at the end
+// of the given paragraph.
+// This is called after all blocks are parsed because paragraphs in
+// tight lists are converted into text blocks that can not have attributes.
+func parseParagraphAttributes(node ast.Node, source []byte) {
+	lines := node.Lines()
+	lastIndex := lines.Len() - 1
+	if lastIndex < 0 {
+		return
+	}
+	lastLine := lines.At(lastIndex)
+	attrs, start := parseTrailingAttributes(lastLine.Value(source))
+	// paragraphs that have only attributes are not attributes, and
+	// empty attributes like '{}' are texts.
+	if start < 0 || len(attrs) == 0 || (start == 0 && lastIndex == 0) {
+		return
+	}
+	for _, attr := range attrs {
+		node.SetAttribute(attr.Name, attr.Value)
+	}
+	if start == 0 {
+		lines.SetSliced(0, lastIndex)
+		lastLine = lines.At(lastIndex - 1)
+	} else {
+		lastLine = lastLine.WithStop(lastLine.Start + start)
+	}
+	lines.Set(lines.Len()-1, lastLine.TrimRightSpace(source))
+}
+
 func (b *paragraphParser) CanInterruptParagraph() bool {
 	return false
 }
@@ -2,6 +2,7 @@
 package parser
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"strings"
 	"sync"
@@ -99,6 +100,11 @@ func (s *ids) Generate(value []byte, kind ast.NodeKind) []byte {
 			result = []byte("id")
 		}
 	}
+	return s.unique(result)
+}
+
+// unique returns the given id with a '-N' suffix if the id is already used.
+func (s *ids) unique(result []byte) []byte {
 	if _, ok := s.values[util.BytesToReadOnlyString(result)]; !ok {
 		s.values[util.BytesToReadOnlyString(result)] = true
 		return result
@@ -151,7 +157,8 @@ type Context interface {
 	// the given label exists, otherwise (nil, false).
 	Reference(label string) (Reference, bool)
 
-	// References returns a list of references.
+	// References returns a list of references in the order they are defined.
+	// References that are never used are also included.
 	References() []Reference
 
 	// IDs returns a collection of the element ids.
@@ -223,6 +230,7 @@ type parseContext struct {
 	store         []interface{}
 	ids           IDs
 	refs          map[string]Reference
+	refList       []Reference
 	blockOffset   int
 	blockIndent   int
 	delimiters    *Delimiter
@@ -242,6 +250,7 @@ func NewContext(options ...ContextOption) Context {
 	return &parseContext{
 		store:         make([]interface{}, ContextKeyMax+1),
 		refs:          map[string]Reference{},
+		refList:       []Reference{},
 		ids:           cfg.IDs,
 		blockOffset:   -1,
 		blockIndent:   -1,
@@ -251,6 +260,70 @@ func NewContext(options ...ContextOption) Context {
 	}
 }
 
+// reset clears all values in this context so that the context can be
+// reused for parsing other documents.
+func (p *parseContext) reset(ids IDs) {
+	if len(p.store) < int(ContextKeyMax)+1 {
+		p.store = make([]interface{}, ContextKeyMax+1)
+	} else {
+		for i := range p.store {
+			p.store[i] = nil
+		}
+	}
+	for k := range p.refs {
+		delete(p.refs, k)
+	}
+	p.refList = p.refList[:0]
+	p.ids = ids
+	p.blockOffset = -1
+	p.blockIndent = -1
+	p.delimiters = nil
+	p.lastDelimiter = nil
+	p.openedBlocks = p.openedBlocks[:0]
+}
+
+// A ContextPool is a set of Contexts that can be reused for parsing
+// documents. A ContextPool is safe for concurrent use by multiple goroutines.
+type ContextPool struct {
+	options []ContextOption
+	pool    sync.Pool
+}
+
+// NewContextPool returns a new ContextPool that creates Contexts with
+// the given options.
+func NewContextPool(options ...ContextOption) *ContextPool {
+	p := &ContextPool{
+		options: options,
+	}
+	p.pool.New = func() interface{} {
+		return NewContext(p.options...)
+	}
+	return p
+}
+
+// Get returns a Context that has no values.
+func (p *ContextPool) Get() Context {
+	return p.pool.Get().(Context)
+}
+
+// Put clears values of the given Context and adds it to the pool.
+// The Context and values obtained from it must not be used after calling Put.
+// Contexts that are not created by NewContext are not added to the pool.
+func (p *ContextPool) Put(pc Context) {
+	c, ok := pc.(*parseContext)
+	if !ok {
+		return
+	}
+	cfg := &ContextConfig{
+		IDs: newIDs(),
+	}
+	for _, option := range p.options {
+		option(cfg)
+	}
+	c.reset(cfg.IDs)
+	p.pool.Put(c)
+}
+
 func (p *parseContext) Get(key ContextKey) interface{} {
 	return p.store[key]
 }
@@ -353,6 +426,7 @@ func (p *parseContext) AddReference(ref Reference) {
 	key := util.ToLinkReference(ref.Label())
 	if _, ok := p.refs[key]; !ok {
 		p.refs[key] = ref
+		p.refList = append(p.refList, ref)
 	}
 }
 
@@ -362,16 +436,14 @@ func (p *parseContext) Reference(label string) (Reference, bool) {
 }
 
 func (p *parseContext) References() []Reference {
-	ret := make([]Reference, 0, len(p.refs))
-	for _, v := range p.refs {
-		ret = append(ret, v)
-	}
+	ret := make([]Reference, len(p.refList))
+	copy(ret, p.refList)
 	return ret
 }
 
 func (p *parseContext) String() string {
 	refs := []string{}
-	for _, r := range p.refs {
+	for _, r := range p.refList {
 		refs = append(refs, r.String())
 	}
 
@@ -431,6 +503,11 @@ type Config struct {
 	ParagraphTransformers util.PrioritizedSlice /*<ParagraphTransformer>*/
 	ASTTransformers       util.PrioritizedSlice /*<ASTTransformer>*/
 	EscapedSpace          bool
+	MergeAdjacentText     bool
+	NewlineHardBreaks     bool
+	MaxNestingDepth       int
+	MaxEmphasisNesting    int
+	Cache                 Cache
 }
 
 // NewConfig returns a new Config.
@@ -467,6 +544,40 @@ func WithAttribute() Option {
 	return &withAttribute{}
 }
 
+// CJKFlanking is an option name used in WithCJKFlanking.
+const optCJKFlanking OptionName = "CJKFlanking"
+
+type withCJKFlanking struct {
+}
+
+func (o *withCJKFlanking) SetParserOption(c *Config) {
+	c.Options[optCJKFlanking] = true
+}
+
+// WithCJKFlanking is a functional option that allows emphasis delimiters
+// adjacent to east asian wide characters to open and close emphasis even if
+// the delimiters are followed or preceded by punctuations like '「'.
+func WithCJKFlanking() Option {
+	return &withCJKFlanking{}
+}
+
+// EncodeDestinationSpaces is an option name used in WithEncodeDestinationSpaces.
+const optEncodeDestinationSpaces OptionName = "EncodeDestinationSpaces"
+
+type withEncodeDestinationSpaces struct {
+}
+
+func (o *withEncodeDestinationSpaces) SetParserOption(c *Config) {
+	c.Options[optEncodeDestinationSpaces] = true
+}
+
+// WithEncodeDestinationSpaces is a functional option that allows unescaped
+// spaces in destinations of inline links and images like '[x](a b)'.
+// Spaces are percent-encoded as '%20'.
+func WithEncodeDestinationSpaces() Option {
+	return &withEncodeDestinationSpaces{}
+}
+
 // A Parser interface parses Markdown text into AST nodes.
 type Parser interface {
 	// Parse parses the given Markdown text into AST nodes.
@@ -588,6 +699,7 @@ func DefaultBlockParsers() []util.PrioritizedValue {
 		util.Prioritized(NewFencedCodeBlockParser(), 700),
 		util.Prioritized(NewBlockquoteParser(), 800),
 		util.Prioritized(NewHTMLBlockParser(), 900),
+		util.Prioritized(NewBlockAttributeParser(), 950),
 		util.Prioritized(NewParagraphParser(), 1000),
 	}
 }
@@ -637,6 +749,12 @@ type parser struct {
 	paragraphTransformers []ParagraphTransformer
 	astTransformers       []ASTTransformer
 	escapedSpace          bool
+	mergeAdjacentText     bool
+	newlineHardBreaks     bool
+	maxNestingDepth       int
+	maxEmphasisNesting    int
+	attribute             bool
+	cache                 Cache
 	config                *Config
 	initSync              sync.Once
 }
@@ -709,6 +827,146 @@ func WithEscapedSpace() Option {
 	return &withEscapedSpace{}
 }
 
+type withMergeAdjacentText struct {
+}
+
+func (o *withMergeAdjacentText) SetParserOption(c *Config) {
+	c.MergeAdjacentText = true
+}
+
+// WithMergeAdjacentText is a functional option indicates that adjacent
+// text nodes whose segments are contiguous should be merged into a single
+// text node after all ASTTransformers have been applied.
+func WithMergeAdjacentText() Option {
+	return &withMergeAdjacentText{}
+}
+
+type withNewlineHardBreaks struct {
+}
+
+func (o *withNewlineHardBreaks) SetParserOption(c *Config) {
+	c.NewlineHardBreaks = true
+}
+
+// WithNewlineHardBreaks is a functional option indicates that newlines in
+// paragraphs should be parsed as hard line breaks instead of soft line
+// breaks.
+func WithNewlineHardBreaks() Option {
+	return &withNewlineHardBreaks{}
+}
+
+type withMaxNestingDepth struct {
+	value int
+}
+
+func (o *withMaxNestingDepth) SetParserOption(c *Config) {
+	c.MaxNestingDepth = o.value
+}
+
+// WithMaxNestingDepth is a functional option that limits nesting of
+// container blocks like blockquotes and lists, emphases and links to the
+// given depth. Blocks nested deeper than the limit are parsed as paragraphs,
+// and delimiters and brackets of emphases and links beyond the limit are
+// parsed as texts. Delimiters and brackets that are never matched are not
+// counted. A list and its items are counted as one level.
+// This is useful for parsing untrusted documents.
+// A value less than or equal to 0 means unlimited.
+func WithMaxNestingDepth(n int) Option {
+	return &withMaxNestingDepth{n}
+}
+
+type withMaxEmphasisNesting struct {
+	value int
+}
+
+func (o *withMaxEmphasisNesting) SetParserOption(c *Config) {
+	c.MaxEmphasisNesting = o.value
+}
+
+// WithMaxEmphasisNesting is a functional option that limits nesting of
+// emphases to the given depth. Delimiters of emphases beyond the limit are
+// parsed as texts, and delimiters that are never matched are not counted.
+// Unlike WithMaxNestingDepth, blocks and links are not limited.
+// A value less than or equal to 0 means unlimited.
+func WithMaxEmphasisNesting(n int) Option {
+	return &withMaxEmphasisNesting{n}
+}
+
+// A Cache interface caches parsed ASTs keyed by SHA-256 hashes of sources.
+// Implementations must be safe for concurrent use if the parser is used
+// concurrently.
+type Cache interface {
+	// Get returns a cached entry of the source that has the given hash.
+	Get(hash [sha256.Size]byte) (*CacheEntry, bool)
+
+	// Put caches the given entry of the source that has the given hash.
+	Put(hash [sha256.Size]byte, entry *CacheEntry)
+}
+
+// A CacheEntry struct is a parsed AST and values of the parser.Context
+// that are set while parsing the AST.
+type CacheEntry struct {
+	// Node is a parsed AST.
+	Node ast.Node
+
+	values     map[ContextKey]interface{}
+	references []Reference
+}
+
+func newCacheEntry(node ast.Node, pc Context) *CacheEntry {
+	entry := &CacheEntry{
+		Node:       node,
+		values:     map[ContextKey]interface{}{},
+		references: pc.References(),
+	}
+	for key := ContextKey(1); key <= ContextKeyMax; key++ {
+		if v := pc.Get(key); v != nil {
+			entry.values[key] = v
+		}
+	}
+	return entry
+}
+
+// restore sets values of this entry to the given context as if the AST
+// was parsed with the context.
+func (e *CacheEntry) restore(pc Context) {
+	for key, v := range e.values {
+		pc.Set(key, v)
+	}
+	for _, ref := range e.references {
+		pc.AddReference(ref)
+	}
+	_ = ast.Walk(e.Node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		if id, ok := n.AttributeString("id"); ok {
+			if v, ok := id.([]byte); ok {
+				pc.IDs().Put(v)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+type withCache struct {
+	value Cache
+}
+
+func (o *withCache) SetParserOption(c *Config) {
+	c.Cache = o.value
+}
+
+// WithCache is a functional option that allow you to cache parsed ASTs.
+// Parsing is skipped if the cache has an AST of the same source, and
+// references, ids and values set to the parser.Context while parsing the
+// AST are restored to the given parser.Context.
+// Cached ASTs and the restored values are shared between calls and must
+// not be modified.
+func WithCache(cache Cache) Option {
+	return &withCache{cache}
+}
+
 type withOption struct {
 	name  OptionName
 	value interface{}
@@ -861,6 +1119,12 @@ func (p *parser) Parse(reader text.Reader, opts ...ParseOption) ast.Node {
 			p.addASTTransformer(v, p.config.Options)
 		}
 		p.escapedSpace = p.config.EscapedSpace
+		p.mergeAdjacentText = p.config.MergeAdjacentText
+		p.newlineHardBreaks = p.config.NewlineHardBreaks
+		p.maxNestingDepth = p.config.MaxNestingDepth
+		p.maxEmphasisNesting = p.config.MaxEmphasisNesting
+		_, p.attribute = p.config.Options[optAttribute]
+		p.cache = p.config.Cache
 		p.config = nil
 	})
 	c := &ParseConfig{}
@@ -871,20 +1135,58 @@ func (p *parser) Parse(reader text.Reader, opts ...ParseOption) ast.Node {
 		c.Context = NewContext()
 	}
 	pc := c.Context
+	var hash [sha256.Size]byte
+	if p.cache != nil {
+		hash = sha256.Sum256(reader.Source())
+		if entry, ok := p.cache.Get(hash); ok {
+			entry.restore(pc)
+			return entry.Node
+		}
+	}
 	root := ast.NewDocument()
 	p.parseBlocks(root, reader, pc)
 
 	blockReader := text.NewBlockReader(reader.Source(), nil)
 	p.walkBlock(root, func(node ast.Node) {
+		if p.attribute && node.Kind() == ast.KindParagraph {
+			parseParagraphAttributes(node, reader.Source())
+		}
 		p.parseBlock(blockReader, node, pc)
 	})
 	for _, at := range p.astTransformers {
 		at.Transform(root, reader, pc)
 	}
+	if p.mergeAdjacentText {
+		mergeAdjacentText(root, reader.Source())
+	}
+	if p.cache != nil {
+		p.cache.Put(hash, newCacheEntry(root, pc))
+	}
 	// root.Dump(reader.Source(), 0)
 	return root
 }
 
+func mergeAdjacentText(root ast.Node, source []byte) {
+	_ = ast.Walk(root, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		for c := node.FirstChild(); c != nil; c = c.NextSibling() {
+			t, ok := c.(*ast.Text)
+			if !ok {
+				continue
+			}
+			for next := t.NextSibling(); next != nil; next = t.NextSibling() {
+				if t.SoftLineBreak() || t.HardLineBreak() || !t.Merge(next, source) {
+					break
+				}
+				node.RemoveChild(node, next)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
 func (p *parser) transformParagraph(node *ast.Paragraph, reader text.Reader, pc Context) bool {
 	for _, pt := range p.paragraphTransformers {
 		pt.Transform(node, reader, pc)
@@ -951,6 +1253,11 @@ retry:
 			bps = p.freeBlockParsers
 		}
 	}
+	if p.isTooDeep(parent) {
+		// container blocks can not be nested more deeply, so
+		// the line is parsed as a paragraph.
+		bps = p.freeBlockParsers
+	}
 	if bps == nil {
 		goto continuable
 	}
@@ -1021,6 +1328,73 @@ continuable:
 	return result
 }
 
+// isTooDeep returns true if container blocks added to the given parent
+// exceed the MaxNestingDepth option.
+func (p *parser) isTooDeep(parent ast.Node) bool {
+	// a list and its items are counted as one level.
+	if p.maxNestingDepth <= 0 || parent.Kind() == ast.KindList {
+		return false
+	}
+	depth := 1
+	for n := parent; n.Parent() != nil; n = n.Parent() {
+		if n.Kind() != ast.KindListItem {
+			depth++
+		}
+	}
+	return depth > p.maxNestingDepth
+}
+
+// emphasisNestingLimit returns the smaller positive value of the
+// MaxNestingDepth and MaxEmphasisNesting options.
+func (p *parser) emphasisNestingLimit() int {
+	limit := p.maxNestingDepth
+	if p.maxEmphasisNesting > 0 && (limit <= 0 || p.maxEmphasisNesting < limit) {
+		limit = p.maxEmphasisNesting
+	}
+	return limit
+}
+
+var inlineNestingKey = NewContextKey()
+
+// inlineNesting holds nesting depths of emphases and links in a block.
+// Depths are counted when delimiters and link brackets are matched, so
+// delimiters and brackets that are never matched are not counted.
+type inlineNesting struct {
+	emphasisLimit  int
+	linkLimit      int
+	emphasisDepths map[ast.Node]int
+	linkDepths     map[ast.Node]int
+}
+
+func getInlineNesting(pc Context) *inlineNesting {
+	v, _ := pc.Get(inlineNestingKey).(*inlineNesting)
+	return v
+}
+
+// maxDepth returns the maximum depth in the given depths of nodes from
+// the given node to the given stop node(exclusive).
+func (s *inlineNesting) maxDepth(from, stop ast.Node, depths map[ast.Node]int) int {
+	max := 0
+	for c := from; c != nil && c != stop; c = c.NextSibling() {
+		if d := s.depth(c, depths); d > max {
+			max = d
+		}
+	}
+	return max
+}
+
+func (s *inlineNesting) depth(n ast.Node, depths map[ast.Node]int) int {
+	if d, ok := depths[n]; ok {
+		return d
+	}
+	if n.FirstChild() == nil {
+		return 0
+	}
+	d := s.maxDepth(n.FirstChild(), nil, depths)
+	depths[n] = d
+	return d
+}
+
 type lineStat struct {
 	lineNum int
 	level   int
@@ -1143,6 +1517,15 @@ func (p *parser) parseBlock(block text.BlockReader, parent ast.Node, pc Context)
 		return
 	}
 	escaped := false
+	if p.maxNestingDepth > 0 || p.maxEmphasisNesting > 0 {
+		pc.Set(inlineNestingKey, &inlineNesting{
+			emphasisLimit:  p.emphasisNestingLimit(),
+			linkLimit:      p.maxNestingDepth,
+			emphasisDepths: map[ast.Node]int{},
+			linkDepths:     map[ast.Node]int{},
+		})
+		defer pc.Set(inlineNestingKey, nil)
+	}
 	source := block.Source()
 	block.Reset(parent.Lines())
 	for {
@@ -1170,7 +1553,11 @@ func (p *parser) parseBlock(block text.BlockReader, parent ast.Node, pc Context)
 			// If the line ends with a newline character, but it is not a hardlineBreak, then it is a softLinebreak
 			// If the line ends with a hardlineBreak, then it cannot end with a softLinebreak
 			// See https://spec.commonmark.org/0.30/#soft-line-breaks
-			lineBreakFlags |= lineBreakSoft
+			if p.newlineHardBreaks {
+				lineBreakFlags |= lineBreakHard
+			} else {
+				lineBreakFlags |= lineBreakSoft
+			}
 		}
 
 		l, startPosition := block.Position()
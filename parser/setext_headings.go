@@ -110,7 +110,7 @@ func (b *setextHeadingParser) Close(node ast.Node, reader text.Reader, pc Contex
 	if b.AutoHeadingID {
 		id, ok := node.AttributeString("id")
 		if !ok {
-			generateAutoHeadingID(heading, reader, pc)
+			generateAutoHeadingID(heading, reader, pc, b.IDFunc)
 		} else {
 			pc.IDs().Put(id.([]byte))
 		}
@@ -4,30 +4,138 @@ import (
 	"bytes"
 	"fmt"
 	"strconv"
+	"strings"
 	"unicode/utf8"
 
 	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/renderer/text"
 	"github.com/yuin/goldmark/util"
 )
 
 // A Config struct has configurations for the HTML based renderers.
 type Config struct {
-	Writer              Writer
-	HardWraps           bool
-	EastAsianLineBreaks bool
-	XHTML               bool
-	Unsafe              bool
+	Writer                 Writer
+	HardWraps              bool
+	ParagraphHardWraps     bool
+	EastAsianLineBreaks    bool
+	XHTML                  bool
+	Unsafe                 bool
+	ImageDecoding          []byte
+	NoCodeSpanTrim         bool
+	OrderedListType        byte
+	URLNormalizer          URLNormalizer
+	CodeBlockLangAttr      bool
+	ImagePlaceholder       []byte
+	CodeBlockInfoClass     bool
+	InlineCodeBlocks       bool
+	NestedOrderedNumbering bool
+
+	// CollapsibleCodeBlocks is a maximum number of lines of code blocks that
+	// are rendered as they are. Longer code blocks are wrapped in
+	// '<details>'. 0 means no code blocks are collapsed.
+	CollapsibleCodeBlocks int
+
+	// CollapsibleLists is a maximum number of items of lists that are
+	// rendered as they are. Remaining items are wrapped in '<details>'.
+	// 0 means no lists are collapsed.
+	CollapsibleLists int
+
+	// HeadingClassByLevel is a map of heading levels to classes of the
+	// headings.
+	HeadingClassByLevel map[int]string
+
+	// ThematicBreakContextClass returns a class of the '<hr>' that follows the
+	// given node. The given node may be nil.
+	ThematicBreakContextClass func(prev ast.Node) string
+
+	// PullQuoteDetector reports whether the given blockquote should be
+	// rendered as a pull-quote figure.
+	PullQuoteDetector func(bq *ast.Blockquote) bool
+
+	// DownloadLinks reports whether a link to the given destination should
+	// have a download attribute.
+	DownloadLinks func(dest []byte) bool
+
+	// PrintURLs reports whether a link to the given destination should be
+	// followed by its URL for printing.
+	PrintURLs func(dest []byte) bool
+
+	// CodeTokenizer splits code in code blocks into tokens that are rendered
+	// as '<span>'s.
+	CodeTokenizer CodeTokenizer
+
+	// FullDocument is options for rendering documents as full HTML pages.
+	// If FullDocument is nil, documents are rendered as fragments.
+	FullDocument *DocumentOptions
+
+	// AutoLinkBreakHints indicates that '<wbr>' elements should be inserted
+	// after '/', '?' and '&' in labels of autolinks.
+	AutoLinkBreakHints bool
 }
 
+// DocumentOptions holds options for rendering full HTML pages.
+type DocumentOptions struct {
+	// Title is a title of the page. If Title is empty, a text of the first
+	// heading of the document is used.
+	Title string
+
+	// Lang is a value of the lang attribute of the html element.
+	Lang string
+
+	// Stylesheets are URLs of stylesheets linked from the page.
+	Stylesheets []string
+}
+
+// A Token struct is a token of code produced by a CodeTokenizer.
+type Token struct {
+	// Class is a class of the token. A token is rendered as
+	// '<span class="tok-Class">'. Tokens without classes are rendered as
+	// texts.
+	Class string
+
+	// Text is a text of the token.
+	Text []byte
+}
+
+// CodeTokenizer is a function that splits the given code into tokens.
+// lang is nil if the code block does not have a language.
+// If CodeTokenizer returns nil, the code is rendered as it is.
+type CodeTokenizer func(lang, code []byte) []Token
+
+// URLNormalizer is a function that normalizes the given link destination.
+// If URLNormalizer returns false, the link is rendered as plain text.
+type URLNormalizer func(dest []byte) ([]byte, bool)
+
 // NewConfig returns a new Config with defaults.
 func NewConfig() Config {
 	return Config{
-		Writer:              DefaultWriter,
-		HardWraps:           false,
-		EastAsianLineBreaks: false,
-		XHTML:               false,
-		Unsafe:              false,
+		Writer:                 DefaultWriter,
+		HardWraps:              false,
+		ParagraphHardWraps:     false,
+		EastAsianLineBreaks:    false,
+		XHTML:                  false,
+		Unsafe:                 false,
+		ImageDecoding:          nil,
+		NoCodeSpanTrim:         false,
+		OrderedListType:        0,
+		URLNormalizer:          nil,
+		CodeBlockLangAttr:      false,
+		ImagePlaceholder:       nil,
+		CodeBlockInfoClass:     false,
+		InlineCodeBlocks:       false,
+		NestedOrderedNumbering: false,
+		CollapsibleCodeBlocks:  0,
+		CollapsibleLists:       0,
+		HeadingClassByLevel:    nil,
+
+		ThematicBreakContextClass: nil,
+		PullQuoteDetector:         nil,
+		DownloadLinks:             nil,
+		PrintURLs:                 nil,
+		CodeTokenizer:             nil,
+		FullDocument:              nil,
+		AutoLinkBreakHints:        false,
 	}
 }
 
@@ -36,6 +144,8 @@ func (c *Config) SetOption(name renderer.OptionName, value interface{}) {
 	switch name {
 	case optHardWraps:
 		c.HardWraps = value.(bool)
+	case optParagraphHardWraps:
+		c.ParagraphHardWraps = value.(bool)
 	case optEastAsianLineBreaks:
 		c.EastAsianLineBreaks = value.(bool)
 	case optXHTML:
@@ -44,6 +154,44 @@ func (c *Config) SetOption(name renderer.OptionName, value interface{}) {
 		c.Unsafe = value.(bool)
 	case optTextWriter:
 		c.Writer = value.(Writer)
+	case optImageDecoding:
+		c.ImageDecoding = value.([]byte)
+	case optNoCodeSpanTrim:
+		c.NoCodeSpanTrim = value.(bool)
+	case optOrderedListType:
+		c.OrderedListType = value.(byte)
+	case optURLNormalizer:
+		c.URLNormalizer = value.(URLNormalizer)
+	case optCodeBlockLangAttr:
+		c.CodeBlockLangAttr = value.(bool)
+	case optImagePlaceholder:
+		c.ImagePlaceholder = value.([]byte)
+	case optCodeBlockInfoClass:
+		c.CodeBlockInfoClass = value.(bool)
+	case optInlineCodeBlocks:
+		c.InlineCodeBlocks = value.(bool)
+	case optNestedOrderedNumbering:
+		c.NestedOrderedNumbering = value.(bool)
+	case optCollapsibleCodeBlocks:
+		c.CollapsibleCodeBlocks = value.(int)
+	case optCollapsibleLists:
+		c.CollapsibleLists = value.(int)
+	case optHeadingClassByLevel:
+		c.HeadingClassByLevel = value.(map[int]string)
+	case optThematicBreakContextClass:
+		c.ThematicBreakContextClass = value.(func(ast.Node) string)
+	case optPullQuoteDetector:
+		c.PullQuoteDetector = value.(func(*ast.Blockquote) bool)
+	case optDownloadLinks:
+		c.DownloadLinks = value.(func([]byte) bool)
+	case optPrintURLs:
+		c.PrintURLs = value.(func([]byte) bool)
+	case optCodeTokenizer:
+		c.CodeTokenizer = value.(CodeTokenizer)
+	case optFullDocument:
+		c.FullDocument = value.(*DocumentOptions)
+	case optAutoLinkBreakHints:
+		c.AutoLinkBreakHints = value.(bool)
 	}
 }
 
@@ -99,6 +247,31 @@ func WithHardWraps() interface {
 	return &withHardWraps{}
 }
 
+// ParagraphHardWraps is an option name used in WithParagraphHardWraps.
+const optParagraphHardWraps renderer.OptionName = "ParagraphHardWraps"
+
+type withParagraphHardWraps struct {
+}
+
+func (o *withParagraphHardWraps) SetConfig(c *renderer.Config) {
+	c.Options[optParagraphHardWraps] = true
+}
+
+func (o *withParagraphHardWraps) SetHTMLOption(c *Config) {
+	c.ParagraphHardWraps = true
+}
+
+// WithParagraphHardWraps is a functional option that indicates whether
+// softline breaks in paragraphs should be rendered as '<br>'.
+// Unlike WithHardWraps, softline breaks in other blocks like headings are
+// rendered as they are. Paragraphs in tight lists are also affected.
+func WithParagraphHardWraps() interface {
+	renderer.Option
+	Option
+} {
+	return &withParagraphHardWraps{}
+}
+
 // EastAsianLineBreaks is an option name used in WithEastAsianLineBreaks.
 const optEastAsianLineBreaks renderer.OptionName = "EastAsianLineBreaks"
 
@@ -168,6 +341,470 @@ func WithUnsafe() interface {
 	return &withUnsafe{}
 }
 
+// ImageDecoding is an option name used in WithImageDecoding.
+const optImageDecoding renderer.OptionName = "ImageDecoding"
+
+type withImageDecoding struct {
+	value []byte
+}
+
+func (o *withImageDecoding) SetConfig(c *renderer.Config) {
+	c.Options[optImageDecoding] = o.value
+}
+
+func (o *withImageDecoding) SetHTMLOption(c *Config) {
+	c.ImageDecoding = o.value
+}
+
+// WithImageDecoding is a functional option that sets a decoding attribute
+// (e.g. "async") to all images.
+func WithImageDecoding(value string) interface {
+	renderer.Option
+	Option
+} {
+	return &withImageDecoding{[]byte(value)}
+}
+
+// NoCodeSpanTrim is an option name used in WithoutCodeSpanTrim.
+const optNoCodeSpanTrim renderer.OptionName = "NoCodeSpanTrim"
+
+type withoutCodeSpanTrim struct {
+}
+
+func (o *withoutCodeSpanTrim) SetConfig(c *renderer.Config) {
+	c.Options[optNoCodeSpanTrim] = true
+}
+
+func (o *withoutCodeSpanTrim) SetHTMLOption(c *Config) {
+	c.NoCodeSpanTrim = true
+}
+
+// WithoutCodeSpanTrim is a functional option that indicates that a leading and
+// trailing space stripped from code spans by the CommonMark rule should be
+// rendered as it is.
+func WithoutCodeSpanTrim() interface {
+	renderer.Option
+	Option
+} {
+	return &withoutCodeSpanTrim{}
+}
+
+// OrderedListType is an option name used in WithOrderedListType.
+const optOrderedListType renderer.OptionName = "OrderedListType"
+
+type withOrderedListType struct {
+	value byte
+}
+
+func (o *withOrderedListType) SetConfig(c *renderer.Config) {
+	c.Options[optOrderedListType] = o.value
+}
+
+func (o *withOrderedListType) SetHTMLOption(c *Config) {
+	c.OrderedListType = o.value
+}
+
+// WithOrderedListType is a functional option that sets a type attribute
+// (one of '1', 'a', 'A', 'i' and 'I') to all ordered lists.
+func WithOrderedListType(value byte) interface {
+	renderer.Option
+	Option
+} {
+	return &withOrderedListType{value}
+}
+
+// URLNormalizer is an option name used in WithURLNormalizer.
+const optURLNormalizer renderer.OptionName = "URLNormalizer"
+
+type withURLNormalizer struct {
+	value URLNormalizer
+}
+
+func (o *withURLNormalizer) SetConfig(c *renderer.Config) {
+	c.Options[optURLNormalizer] = o.value
+}
+
+func (o *withURLNormalizer) SetHTMLOption(c *Config) {
+	c.URLNormalizer = o.value
+}
+
+// WithURLNormalizer is a functional option that normalizes destinations of
+// links, autolinks and images. If the given function returns false,
+// the link is rendered as plain text.
+func WithURLNormalizer(f func(dest []byte) ([]byte, bool)) interface {
+	renderer.Option
+	Option
+} {
+	return &withURLNormalizer{f}
+}
+
+// CodeBlockLangAttr is an option name used in WithCodeBlockLangAttr.
+const optCodeBlockLangAttr renderer.OptionName = "CodeBlockLangAttr"
+
+type withCodeBlockLangAttr struct {
+}
+
+func (o *withCodeBlockLangAttr) SetConfig(c *renderer.Config) {
+	c.Options[optCodeBlockLangAttr] = true
+}
+
+func (o *withCodeBlockLangAttr) SetHTMLOption(c *Config) {
+	c.CodeBlockLangAttr = true
+}
+
+// WithCodeBlockLangAttr is a functional option that renders a data-lang
+// attribute that indicates a language of fenced code blocks on '<pre>'.
+func WithCodeBlockLangAttr() interface {
+	renderer.Option
+	Option
+} {
+	return &withCodeBlockLangAttr{}
+}
+
+// CodeBlockInfoClass is an option name used in WithCodeBlockInfoClass.
+const optCodeBlockInfoClass renderer.OptionName = "CodeBlockInfoClass"
+
+type withCodeBlockInfoClass struct {
+}
+
+func (o *withCodeBlockInfoClass) SetConfig(c *renderer.Config) {
+	c.Options[optCodeBlockInfoClass] = true
+}
+
+func (o *withCodeBlockInfoClass) SetHTMLOption(c *Config) {
+	c.CodeBlockInfoClass = true
+}
+
+// WithCodeBlockInfoClass is a functional option that renders words in
+// info strings of fenced code blocks that follow the language as
+// additional classes of '<code>'.
+func WithCodeBlockInfoClass() interface {
+	renderer.Option
+	Option
+} {
+	return &withCodeBlockInfoClass{}
+}
+
+// InlineCodeBlocks is an option name used in WithInlineCodeBlocks.
+const optInlineCodeBlocks renderer.OptionName = "InlineCodeBlocks"
+
+type withInlineCodeBlocks struct {
+}
+
+func (o *withInlineCodeBlocks) SetConfig(c *renderer.Config) {
+	c.Options[optInlineCodeBlocks] = true
+}
+
+func (o *withInlineCodeBlocks) SetHTMLOption(c *Config) {
+	c.InlineCodeBlocks = true
+}
+
+// WithInlineCodeBlocks is a functional option that renders fenced code
+// blocks that have the '{inline}' flag in their info strings without '<pre>'.
+func WithInlineCodeBlocks() interface {
+	renderer.Option
+	Option
+} {
+	return &withInlineCodeBlocks{}
+}
+
+// NestedOrderedNumbering is an option name used in WithNestedOrderedNumbering.
+const optNestedOrderedNumbering renderer.OptionName = "NestedOrderedNumbering"
+
+type withNestedOrderedNumbering struct {
+}
+
+func (o *withNestedOrderedNumbering) SetConfig(c *renderer.Config) {
+	c.Options[optNestedOrderedNumbering] = true
+}
+
+func (o *withNestedOrderedNumbering) SetHTMLOption(c *Config) {
+	c.NestedOrderedNumbering = true
+}
+
+// WithNestedOrderedNumbering is a functional option that renders
+// hierarchical numbers of ordered list items like '1.', '1.1' and '1.2'
+// as data-number attributes.
+func WithNestedOrderedNumbering() interface {
+	renderer.Option
+	Option
+} {
+	return &withNestedOrderedNumbering{}
+}
+
+// CollapsibleCodeBlocks is an option name used in WithCollapsibleCodeBlocks.
+const optCollapsibleCodeBlocks renderer.OptionName = "CollapsibleCodeBlocks"
+
+type withCollapsibleCodeBlocks struct {
+	value int
+}
+
+func (o *withCollapsibleCodeBlocks) SetConfig(c *renderer.Config) {
+	c.Options[optCollapsibleCodeBlocks] = o.value
+}
+
+func (o *withCollapsibleCodeBlocks) SetHTMLOption(c *Config) {
+	c.CollapsibleCodeBlocks = o.value
+}
+
+// WithCollapsibleCodeBlocks is a functional option that wraps code blocks
+// that have more than maxLines lines in '<details>' so that they are
+// rendered collapsed.
+func WithCollapsibleCodeBlocks(maxLines int) interface {
+	renderer.Option
+	Option
+} {
+	return &withCollapsibleCodeBlocks{maxLines}
+}
+
+// CollapsibleLists is an option name used in WithCollapsibleLists.
+const optCollapsibleLists renderer.OptionName = "CollapsibleLists"
+
+type withCollapsibleLists struct {
+	value int
+}
+
+func (o *withCollapsibleLists) SetConfig(c *renderer.Config) {
+	c.Options[optCollapsibleLists] = o.value
+}
+
+func (o *withCollapsibleLists) SetHTMLOption(c *Config) {
+	c.CollapsibleLists = o.value
+}
+
+// WithCollapsibleLists is a functional option that renders only the first
+// maxItems items of lists as they are. Remaining items are rendered as
+// another list wrapped in '<details>' so that they are rendered collapsed.
+func WithCollapsibleLists(maxItems int) interface {
+	renderer.Option
+	Option
+} {
+	return &withCollapsibleLists{maxItems}
+}
+
+// HeadingClassByLevel is an option name used in WithHeadingClassByLevel.
+const optHeadingClassByLevel renderer.OptionName = "HeadingClassByLevel"
+
+type withHeadingClassByLevel struct {
+	value map[int]string
+}
+
+func (o *withHeadingClassByLevel) SetConfig(c *renderer.Config) {
+	c.Options[optHeadingClassByLevel] = o.value
+}
+
+func (o *withHeadingClassByLevel) SetHTMLOption(c *Config) {
+	c.HeadingClassByLevel = o.value
+}
+
+// WithHeadingClassByLevel is a functional option that renders classes of
+// headings according to their levels like '<h2 class="h2-style">'.
+// Classes are appended to classes that are specified by attributes.
+func WithHeadingClassByLevel(classes map[int]string) interface {
+	renderer.Option
+	Option
+} {
+	return &withHeadingClassByLevel{classes}
+}
+
+// ThematicBreakContextClass is an option name used in WithThematicBreakContextClass.
+const optThematicBreakContextClass renderer.OptionName = "ThematicBreakContextClass"
+
+type withThematicBreakContextClass struct {
+	value func(ast.Node) string
+}
+
+func (o *withThematicBreakContextClass) SetConfig(c *renderer.Config) {
+	c.Options[optThematicBreakContextClass] = o.value
+}
+
+func (o *withThematicBreakContextClass) SetHTMLOption(c *Config) {
+	c.ThematicBreakContextClass = o.value
+}
+
+// WithThematicBreakContextClass is a functional option that renders a class
+// returned by the given function on '<hr>'. The function receives a previous
+// sibling of the thematic break, or nil if the thematic break is the first
+// child. If the function returns an empty string, no classes are rendered.
+func WithThematicBreakContextClass(f func(prev ast.Node) string) interface {
+	renderer.Option
+	Option
+} {
+	return &withThematicBreakContextClass{f}
+}
+
+// ImagePlaceholder is an option name used in WithImagePlaceholder.
+const optImagePlaceholder renderer.OptionName = "ImagePlaceholder"
+
+type withImagePlaceholder struct {
+	value []byte
+}
+
+func (o *withImagePlaceholder) SetConfig(c *renderer.Config) {
+	c.Options[optImagePlaceholder] = o.value
+}
+
+func (o *withImagePlaceholder) SetHTMLOption(c *Config) {
+	c.ImagePlaceholder = o.value
+}
+
+// WithImagePlaceholder is a functional option that renders the given HTML
+// in place of images whose destinations are rejected by the URLNormalizer or
+// considered dangerous.
+func WithImagePlaceholder(html string) interface {
+	renderer.Option
+	Option
+} {
+	return &withImagePlaceholder{[]byte(html)}
+}
+
+// PullQuoteDetector is an option name used in WithPullQuoteDetector.
+const optPullQuoteDetector renderer.OptionName = "PullQuoteDetector"
+
+type withPullQuoteDetector struct {
+	value func(*ast.Blockquote) bool
+}
+
+func (o *withPullQuoteDetector) SetConfig(c *renderer.Config) {
+	c.Options[optPullQuoteDetector] = o.value
+}
+
+func (o *withPullQuoteDetector) SetHTMLOption(c *Config) {
+	c.PullQuoteDetector = o.value
+}
+
+// WithPullQuoteDetector is a functional option that renders blockquotes
+// detected by the given function as '<figure class="pullquote">'.
+func WithPullQuoteDetector(f func(bq *ast.Blockquote) bool) interface {
+	renderer.Option
+	Option
+} {
+	return &withPullQuoteDetector{f}
+}
+
+// DownloadLinks is an option name used in WithDownloadLinks.
+const optDownloadLinks renderer.OptionName = "DownloadLinks"
+
+type withDownloadLinks struct {
+	value func([]byte) bool
+}
+
+func (o *withDownloadLinks) SetConfig(c *renderer.Config) {
+	c.Options[optDownloadLinks] = o.value
+}
+
+func (o *withDownloadLinks) SetHTMLOption(c *Config) {
+	c.DownloadLinks = o.value
+}
+
+// WithDownloadLinks is a functional option that renders a download
+// attribute on links whose destinations match the given function.
+func WithDownloadLinks(f func(dest []byte) bool) interface {
+	renderer.Option
+	Option
+} {
+	return &withDownloadLinks{f}
+}
+
+// PrintURLs is an option name used in WithPrintURLs.
+const optPrintURLs renderer.OptionName = "PrintURLs"
+
+type withPrintURLs struct {
+	value func(dest []byte) bool
+}
+
+func (o *withPrintURLs) SetConfig(c *renderer.Config) {
+	c.Options[optPrintURLs] = o.value
+}
+
+func (o *withPrintURLs) SetHTMLOption(c *Config) {
+	c.PrintURLs = o.value
+}
+
+// WithPrintURLs is a functional option that renders URLs of links and
+// autolinks whose destinations match the given function like
+// '<span class="print-url">URL</span>' after the links.
+// Print stylesheets can show these spans to make URLs visible on paper.
+func WithPrintURLs(f func(dest []byte) bool) interface {
+	renderer.Option
+	Option
+} {
+	return &withPrintURLs{f}
+}
+
+// AutoLinkBreakHints is an option name used in WithAutoLinkBreakHints.
+const optAutoLinkBreakHints renderer.OptionName = "AutoLinkBreakHints"
+
+type withAutoLinkBreakHints struct {
+}
+
+func (o *withAutoLinkBreakHints) SetConfig(c *renderer.Config) {
+	c.Options[optAutoLinkBreakHints] = true
+}
+
+func (o *withAutoLinkBreakHints) SetHTMLOption(c *Config) {
+	c.AutoLinkBreakHints = true
+}
+
+// WithAutoLinkBreakHints is a functional option that inserts '<wbr>'
+// elements after '/', '?' and '&' in labels of autolinks, so long URLs
+// can be wrapped by browsers.
+func WithAutoLinkBreakHints() interface {
+	renderer.Option
+	Option
+} {
+	return &withAutoLinkBreakHints{}
+}
+
+// CodeTokenizer is an option name used in WithCodeTokenizer.
+const optCodeTokenizer renderer.OptionName = "CodeTokenizer"
+
+type withCodeTokenizer struct {
+	value CodeTokenizer
+}
+
+func (o *withCodeTokenizer) SetConfig(c *renderer.Config) {
+	c.Options[optCodeTokenizer] = o.value
+}
+
+func (o *withCodeTokenizer) SetHTMLOption(c *Config) {
+	c.CodeTokenizer = o.value
+}
+
+// WithCodeTokenizer is a functional option that renders code in code blocks
+// as tokens produced by the given function. Each token is rendered as
+// '<span class="tok-Class">'. This is useful for syntax highlighting.
+func WithCodeTokenizer(f func(lang, code []byte) []Token) interface {
+	renderer.Option
+	Option
+} {
+	return &withCodeTokenizer{f}
+}
+
+// FullDocument is an option name used in WithFullDocument.
+const optFullDocument renderer.OptionName = "FullDocument"
+
+type withFullDocument struct {
+	value *DocumentOptions
+}
+
+func (o *withFullDocument) SetConfig(c *renderer.Config) {
+	c.Options[optFullDocument] = o.value
+}
+
+func (o *withFullDocument) SetHTMLOption(c *Config) {
+	c.FullDocument = o.value
+}
+
+// WithFullDocument is a functional option that renders documents as full
+// HTML pages that have '<!doctype html>', '<head>' and '<body>'.
+func WithFullDocument(opts DocumentOptions) interface {
+	renderer.Option
+	Option
+} {
+	return &withFullDocument{&opts}
+}
+
 // A Renderer struct is an implementation of renderer.NodeRenderer that renders
 // nodes as (X)HTML.
 type Renderer struct {
@@ -214,6 +851,13 @@ func (r *Renderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
 	reg.Register(ast.KindString, r.renderString)
 }
 
+func (r *Renderer) normalizeURL(dest []byte) ([]byte, bool) {
+	if r.URLNormalizer == nil {
+		return dest, true
+	}
+	return r.URLNormalizer(dest)
+}
+
 func (r *Renderer) writeLines(w util.BufWriter, source []byte, n ast.Node) {
 	l := n.Lines().Len()
 	for i := 0; i < l; i++ {
@@ -222,6 +866,37 @@ func (r *Renderer) writeLines(w util.BufWriter, source []byte, n ast.Node) {
 	}
 }
 
+// writeCode writes lines of the given code block as tokens produced by the
+// CodeTokenizer option if possible.
+func (r *Renderer) writeCode(w util.BufWriter, source []byte, n ast.Node, language []byte) {
+	if r.CodeTokenizer == nil {
+		r.writeLines(w, source, n)
+		return
+	}
+	var code bytes.Buffer
+	l := n.Lines().Len()
+	for i := 0; i < l; i++ {
+		line := n.Lines().At(i)
+		code.Write(line.Value(source))
+	}
+	tokens := r.CodeTokenizer(language, code.Bytes())
+	if tokens == nil {
+		r.writeLines(w, source, n)
+		return
+	}
+	for _, token := range tokens {
+		if len(token.Class) == 0 {
+			r.Writer.RawWrite(w, token.Text)
+			continue
+		}
+		_, _ = w.WriteString(`<span class="tok-`)
+		_, _ = w.Write(util.EscapeHTML([]byte(token.Class)))
+		_, _ = w.WriteString(`">`)
+		r.Writer.RawWrite(w, token.Text)
+		_, _ = w.WriteString("</span>")
+	}
+}
+
 // GlobalAttributeFilter defines attribute names which any elements can have.
 var GlobalAttributeFilter = util.NewBytesFilter(
 	[]byte("accesskey"),
@@ -254,10 +929,67 @@ var GlobalAttributeFilter = util.NewBytesFilter(
 )
 
 func (r *Renderer) renderDocument(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
-	// nothing to do
+	if r.FullDocument == nil {
+		return ast.WalkContinue, nil
+	}
+	if entering {
+		r.renderDocumentHead(w, source, node)
+	} else {
+		_, _ = w.WriteString("</body>\n</html>\n")
+	}
 	return ast.WalkContinue, nil
 }
 
+func (r *Renderer) renderDocumentHead(w util.BufWriter, source []byte, node ast.Node) {
+	opts := r.FullDocument
+	_, _ = w.WriteString("<!doctype html>\n<html")
+	if len(opts.Lang) != 0 {
+		_, _ = w.WriteString(` lang="`)
+		_, _ = w.Write(util.EscapeHTML([]byte(opts.Lang)))
+		_ = w.WriteByte('"')
+	}
+	_, _ = w.WriteString(">\n<head>\n<meta charset=\"utf-8\"")
+	r.writeVoidTagEnd(w)
+	_, _ = w.WriteString("<title>")
+	if len(opts.Title) != 0 {
+		_, _ = w.Write(util.EscapeHTML([]byte(opts.Title)))
+	} else if heading := firstHeading(node); heading != nil {
+		// the title is a plain text, so references and escapes in the
+		// heading must be resolved before escaping it.
+		var buf bytes.Buffer
+		_ = text.NewRenderer().Render(&buf, source, heading)
+		_, _ = w.Write(util.EscapeHTML(buf.Bytes()))
+	}
+	_, _ = w.WriteString("</title>\n")
+	for _, href := range opts.Stylesheets {
+		_, _ = w.WriteString(`<link rel="stylesheet" href="`)
+		_, _ = w.Write(util.EscapeHTML(util.URLEscape([]byte(href), true)))
+		_ = w.WriteByte('"')
+		r.writeVoidTagEnd(w)
+	}
+	_, _ = w.WriteString("</head>\n<body>\n")
+}
+
+func (r *Renderer) writeVoidTagEnd(w util.BufWriter) {
+	if r.XHTML {
+		_, _ = w.WriteString(" />\n")
+	} else {
+		_, _ = w.WriteString(">\n")
+	}
+}
+
+func firstHeading(node ast.Node) ast.Node {
+	var heading ast.Node
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering && n.Kind() == ast.KindHeading {
+			heading = n
+			return ast.WalkStop, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return heading
+}
+
 // HeadingAttributeFilter defines attribute names which heading elements can have
 var HeadingAttributeFilter = GlobalAttributeFilter
 
@@ -266,7 +998,9 @@ func (r *Renderer) renderHeading(w util.BufWriter, source []byte, node ast.Node,
 	if entering {
 		_, _ = w.WriteString("<h")
 		_ = w.WriteByte("0123456"[n.Level])
-		if n.Attributes() != nil {
+		if class := r.HeadingClassByLevel[n.Level]; len(class) != 0 {
+			RenderAttributesWithClass(w, node, HeadingAttributeFilter, class)
+		} else if n.Attributes() != nil {
 			RenderAttributes(w, node, HeadingAttributeFilter)
 		}
 		_ = w.WriteByte('>')
@@ -284,44 +1018,128 @@ var BlockquoteAttributeFilter = GlobalAttributeFilter.Extend(
 )
 
 func (r *Renderer) renderBlockquote(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	pullQuote := r.PullQuoteDetector != nil && r.PullQuoteDetector(n.(*ast.Blockquote))
 	if entering {
+		if pullQuote {
+			_, _ = w.WriteString("<figure class=\"pullquote\">\n")
+		}
 		if n.Attributes() != nil {
 			_, _ = w.WriteString("<blockquote")
 			RenderAttributes(w, n, BlockquoteAttributeFilter)
-			_ = w.WriteByte('>')
+			_, _ = w.WriteString(">\n")
 		} else {
 			_, _ = w.WriteString("<blockquote>\n")
 		}
 	} else {
 		_, _ = w.WriteString("</blockquote>\n")
+		if pullQuote {
+			_, _ = w.WriteString("</figure>\n")
+		}
 	}
 	return ast.WalkContinue, nil
 }
 
 func (r *Renderer) renderCodeBlock(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
 	if entering {
+		r.renderCollapsibleCodeBlockOpen(w, n)
 		_, _ = w.WriteString("<pre><code>")
-		r.writeLines(w, source, n)
+		r.writeCode(w, source, n, nil)
 	} else {
 		_, _ = w.WriteString("</code></pre>\n")
+		r.renderCollapsibleCodeBlockClose(w, n)
 	}
 	return ast.WalkContinue, nil
 }
 
+// isCollapsibleCodeBlock returns true if the given code block is longer than
+// the CollapsibleCodeBlocks option.
+func (r *Renderer) isCollapsibleCodeBlock(n ast.Node) bool {
+	return r.CollapsibleCodeBlocks > 0 && n.Lines().Len() > r.CollapsibleCodeBlocks
+}
+
+func (r *Renderer) renderCollapsibleCodeBlockOpen(w util.BufWriter, n ast.Node) {
+	if r.isCollapsibleCodeBlock(n) {
+		_, _ = w.WriteString("<details>\n<summary>Show ")
+		_, _ = w.WriteString(strconv.Itoa(n.Lines().Len()))
+		_, _ = w.WriteString(" lines</summary>\n")
+	}
+}
+
+func (r *Renderer) renderCollapsibleCodeBlockClose(w util.BufWriter, n ast.Node) {
+	if r.isCollapsibleCodeBlock(n) {
+		_, _ = w.WriteString("</details>\n")
+	}
+}
+
+var inlineCodeBlockFlag = []byte("{inline}")
+
+// isInlineCodeBlock returns true if the given info string words have the
+// '{inline}' flag.
+func isInlineCodeBlock(words [][]byte) bool {
+	for _, word := range words {
+		if bytes.Equal(word, inlineCodeBlockFlag) {
+			return true
+		}
+	}
+	return false
+}
+
+// FencedCodeBlockAttributeFilter defines attribute names which pre elements
+// of fenced code blocks can have.
+var FencedCodeBlockAttributeFilter = GlobalAttributeFilter
+
 func (r *Renderer) renderFencedCodeBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
 	n := node.(*ast.FencedCodeBlock)
+	var words [][]byte
+	if n.Info != nil {
+		words = bytes.Fields(n.Info.Segment.Value(source))
+	}
+	// fenced code blocks that have the '{inline}' flag are rendered
+	// without '<pre>' for tight layouts.
+	inline := r.InlineCodeBlocks && isInlineCodeBlock(words)
 	if entering {
-		_, _ = w.WriteString("<pre><code")
 		language := n.Language(source)
+		if inline && bytes.Equal(language, inlineCodeBlockFlag) {
+			language = nil
+		}
+		r.renderCollapsibleCodeBlockOpen(w, n)
+		if !inline {
+			_, _ = w.WriteString("<pre")
+			if r.CodeBlockLangAttr && language != nil {
+				_, _ = w.WriteString(` data-lang="`)
+				r.Writer.Write(w, language)
+				_ = w.WriteByte('"')
+			}
+			if n.Attributes() != nil {
+				RenderAttributes(w, n, FencedCodeBlockAttributeFilter)
+			}
+			_ = w.WriteByte('>')
+		}
+		_, _ = w.WriteString("<code")
 		if language != nil {
 			_, _ = w.WriteString(" class=\"language-")
 			r.Writer.Write(w, language)
+			if r.CodeBlockInfoClass {
+				info := n.Info.Segment.Value(source)[len(language):]
+				for _, word := range bytes.Fields(info) {
+					if inline && bytes.Equal(word, inlineCodeBlockFlag) {
+						continue
+					}
+					_ = w.WriteByte(' ')
+					r.Writer.Write(w, word)
+				}
+			}
 			_, _ = w.WriteString("\"")
 		}
 		_ = w.WriteByte('>')
-		r.writeLines(w, source, n)
+		r.writeCode(w, source, n, language)
 	} else {
-		_, _ = w.WriteString("</code></pre>\n")
+		if inline {
+			_, _ = w.WriteString("</code>\n")
+		} else {
+			_, _ = w.WriteString("</code></pre>\n")
+		}
+		r.renderCollapsibleCodeBlockClose(w, n)
 	}
 	return ast.WalkContinue, nil
 }
@@ -365,37 +1183,125 @@ func (r *Renderer) renderList(w util.BufWriter, source []byte, node ast.Node, en
 		tag = "ol"
 	}
 	if entering {
-		_ = w.WriteByte('<')
-		_, _ = w.WriteString(tag)
-		if n.IsOrdered() && n.Start != 1 {
-			fmt.Fprintf(w, " start=\"%d\"", n.Start)
-		}
-		if n.Attributes() != nil {
-			RenderAttributes(w, n, ListAttributeFilter)
-		}
-		_, _ = w.WriteString(">\n")
+		r.renderListOpen(w, n, tag, n.Start, true)
 	} else {
 		_, _ = w.WriteString("</")
 		_, _ = w.WriteString(tag)
 		_, _ = w.WriteString(">\n")
+		if r.isCollapsibleList(n) {
+			_, _ = w.WriteString("</details>\n")
+		}
 	}
 	return ast.WalkContinue, nil
 }
 
+func (r *Renderer) renderListOpen(w util.BufWriter, n *ast.List, tag string, start int, attributes bool) {
+	_ = w.WriteByte('<')
+	_, _ = w.WriteString(tag)
+	if n.IsOrdered() && start != 1 {
+		fmt.Fprintf(w, " start=\"%d\"", start)
+	}
+	if n.IsOrdered() && r.OrderedListType != 0 {
+		if _, ok := n.AttributeString("type"); !ok {
+			_, _ = w.WriteString(` type="`)
+			_, _ = w.Write(util.EscapeHTML([]byte{r.OrderedListType}))
+			_ = w.WriteByte('"')
+		}
+	}
+	if attributes && n.Attributes() != nil {
+		RenderAttributes(w, n, ListAttributeFilter)
+	}
+	_, _ = w.WriteString(">\n")
+}
+
+// isCollapsibleList returns true if the given list has more items than
+// the CollapsibleLists option.
+func (r *Renderer) isCollapsibleList(n ast.Node) bool {
+	return r.CollapsibleLists > 0 && n.ChildCount() > r.CollapsibleLists
+}
+
+// renderCollapsibleListOpen closes the list and opens '<details>' that
+// contains remaining items if the given item is the first item to be
+// collapsed. Attributes of the list are not rendered on the list in
+// '<details>' to avoid duplicated ids.
+func (r *Renderer) renderCollapsibleListOpen(w util.BufWriter, item ast.Node) {
+	n, ok := item.Parent().(*ast.List)
+	if !ok || !r.isCollapsibleList(n) {
+		return
+	}
+	index := 0
+	for c := item.PreviousSibling(); c != nil && index <= r.CollapsibleLists; c = c.PreviousSibling() {
+		index++
+	}
+	if index != r.CollapsibleLists {
+		return
+	}
+	tag := "ul"
+	if n.IsOrdered() {
+		tag = "ol"
+	}
+	_, _ = w.WriteString("</")
+	_, _ = w.WriteString(tag)
+	_, _ = w.WriteString(">\n<details>\n<summary>Show ")
+	rest := n.ChildCount() - index
+	_, _ = w.WriteString(strconv.Itoa(rest))
+	if rest == 1 {
+		_, _ = w.WriteString(" more item</summary>\n")
+	} else {
+		_, _ = w.WriteString(" more items</summary>\n")
+	}
+	r.renderListOpen(w, n, tag, n.Start+index, false)
+}
+
 // ListItemAttributeFilter defines attribute names which list item elements can have.
 var ListItemAttributeFilter = GlobalAttributeFilter.Extend(
 	[]byte("value"),
 )
 
+// nestedOrderedNumber returns a hierarchical number like '1.2' of the given
+// list item. Only directly nested ordered lists are counted.
+// nestedOrderedNumber returns an empty string if the given list item does
+// not belong to an ordered list.
+func nestedOrderedNumber(item ast.Node) string {
+	numbers := []string{}
+	for c := item; c != nil; {
+		list, ok := c.Parent().(*ast.List)
+		if !ok || !list.IsOrdered() {
+			break
+		}
+		number := list.Start
+		for s := list.FirstChild(); s != nil && s != c; s = s.NextSibling() {
+			number++
+		}
+		numbers = append([]string{strconv.Itoa(number)}, numbers...)
+		c = list.Parent()
+		if _, ok := c.(*ast.ListItem); !ok {
+			break
+		}
+	}
+	if len(numbers) == 1 {
+		return numbers[0] + "."
+	}
+	return strings.Join(numbers, ".")
+}
+
 func (r *Renderer) renderListItem(w util.BufWriter, source []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
 	if entering {
+		r.renderCollapsibleListOpen(w, n)
+		_, _ = w.WriteString("<li")
+		if r.NestedOrderedNumbering {
+			if _, ok := n.AttributeString("data-number"); !ok {
+				if number := nestedOrderedNumber(n); number != "" {
+					_, _ = w.WriteString(` data-number="`)
+					_, _ = w.WriteString(number)
+					_ = w.WriteByte('"')
+				}
+			}
+		}
 		if n.Attributes() != nil {
-			_, _ = w.WriteString("<li")
 			RenderAttributes(w, n, ListItemAttributeFilter)
-			_ = w.WriteByte('>')
-		} else {
-			_, _ = w.WriteString("<li>")
 		}
+		_ = w.WriteByte('>')
 		fc := n.FirstChild()
 		if fc != nil {
 			if _, ok := fc.(*ast.TextBlock); !ok {
@@ -449,7 +1355,13 @@ func (r *Renderer) renderThematicBreak(w util.BufWriter, source []byte, n ast.No
 		return ast.WalkContinue, nil
 	}
 	_, _ = w.WriteString("<hr")
-	if n.Attributes() != nil {
+	var class string
+	if r.ThematicBreakContextClass != nil {
+		class = r.ThematicBreakContextClass(n.PreviousSibling())
+	}
+	if len(class) != 0 {
+		RenderAttributesWithClass(w, n, ThematicAttributeFilter, class)
+	} else if n.Attributes() != nil {
 		RenderAttributes(w, n, ThematicAttributeFilter)
 	}
 	if r.XHTML {
@@ -478,12 +1390,16 @@ func (r *Renderer) renderAutoLink(w util.BufWriter, source []byte, node ast.Node
 	if !entering {
 		return ast.WalkContinue, nil
 	}
-	_, _ = w.WriteString(`<a href="`)
-	url := n.URL(source)
+	url, ok := r.normalizeURL(n.URL(source))
 	label := n.Label(source)
+	if !ok {
+		_, _ = w.Write(util.EscapeHTML(label))
+		return ast.WalkContinue, nil
+	}
 	if n.AutoLinkType == ast.AutoLinkEmail && !bytes.HasPrefix(bytes.ToLower(url), []byte("mailto:")) {
-		_, _ = w.WriteString("mailto:")
+		url = append([]byte("mailto:"), url...)
 	}
+	_, _ = w.WriteString(`<a href="`)
 	_, _ = w.Write(util.EscapeHTML(util.URLEscape(url, false)))
 	if n.Attributes() != nil {
 		_ = w.WriteByte('"')
@@ -492,11 +1408,39 @@ func (r *Renderer) renderAutoLink(w util.BufWriter, source []byte, node ast.Node
 	} else {
 		_, _ = w.WriteString(`">`)
 	}
-	_, _ = w.Write(util.EscapeHTML(label))
+	if r.AutoLinkBreakHints {
+		writeBreakHints(w, label)
+	} else {
+		_, _ = w.Write(util.EscapeHTML(label))
+	}
 	_, _ = w.WriteString(`</a>`)
+	if r.PrintURLs != nil && r.PrintURLs(url) && (r.Unsafe || !IsDangerousURL(url)) {
+		_, _ = w.WriteString(`<span class="print-url">`)
+		_, _ = w.Write(util.EscapeHTML(url))
+		_, _ = w.WriteString("</span>")
+	}
 	return ast.WalkContinue, nil
 }
 
+// writeBreakHints writes the given label with '<wbr>' after '/', '?' and
+// '&'. Consecutive slashes like '//' get only one break hint after them.
+func writeBreakHints(w util.BufWriter, label []byte) {
+	start := 0
+	for i := 0; i < len(label)-1; i++ {
+		c := label[i]
+		if c != '/' && c != '?' && c != '&' {
+			continue
+		}
+		if c == '/' && label[i+1] == '/' {
+			continue
+		}
+		_, _ = w.Write(util.EscapeHTML(label[start : i+1]))
+		_, _ = w.WriteString("<wbr>")
+		start = i + 1
+	}
+	_, _ = w.Write(util.EscapeHTML(label[start:]))
+}
+
 // CodeAttributeFilter defines attribute names which code elements can have.
 var CodeAttributeFilter = GlobalAttributeFilter
 
@@ -509,6 +1453,10 @@ func (r *Renderer) renderCodeSpan(w util.BufWriter, source []byte, n ast.Node, e
 		} else {
 			_, _ = w.WriteString("<code>")
 		}
+		trimmed := r.NoCodeSpanTrim && isTrimmedCodeSpan(n, source)
+		if trimmed {
+			_ = w.WriteByte(' ')
+		}
 		for c := n.FirstChild(); c != nil; c = c.NextSibling() {
 			segment := c.(*ast.Text).Segment
 			value := segment.Value(source)
@@ -519,12 +1467,33 @@ func (r *Renderer) renderCodeSpan(w util.BufWriter, source []byte, n ast.Node, e
 				r.Writer.RawWrite(w, value)
 			}
 		}
+		if trimmed {
+			_ = w.WriteByte(' ')
+		}
 		return ast.WalkSkipChildren, nil
 	}
 	_, _ = w.WriteString("</code>")
 	return ast.WalkContinue, nil
 }
 
+// isTrimmedCodeSpan returns true if a leading and trailing space have been
+// stripped from the given code span by the parser.
+func isTrimmedCodeSpan(n ast.Node, source []byte) bool {
+	first, ok := n.FirstChild().(*ast.Text)
+	if !ok {
+		return false
+	}
+	last, ok := n.LastChild().(*ast.Text)
+	if !ok {
+		return false
+	}
+	start := first.Segment.Start - 1
+	stop := last.Segment.Stop
+	return start >= 0 && stop < len(source) &&
+		(source[start] == ' ' || source[start] == '\n') &&
+		(source[stop] == ' ' || source[stop] == '\n')
+}
+
 // EmphasisAttributeFilter defines attribute names which emphasis elements can have.
 var EmphasisAttributeFilter = GlobalAttributeFilter
 
@@ -551,10 +1520,14 @@ func (r *Renderer) renderEmphasis(w util.BufWriter, source []byte, node ast.Node
 
 func (r *Renderer) renderLink(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
 	n := node.(*ast.Link)
+	destination, ok := r.normalizeURL(n.Destination)
+	if !ok {
+		return ast.WalkContinue, nil
+	}
 	if entering {
 		_, _ = w.WriteString("<a href=\"")
-		if r.Unsafe || !IsDangerousURL(n.Destination) {
-			_, _ = w.Write(util.EscapeHTML(util.URLEscape(n.Destination, true)))
+		if r.Unsafe || !IsDangerousURL(destination) {
+			_, _ = w.Write(util.EscapeHTML(util.URLEscape(destination, true)))
 		}
 		_ = w.WriteByte('"')
 		if n.Title != nil {
@@ -562,12 +1535,22 @@ func (r *Renderer) renderLink(w util.BufWriter, source []byte, node ast.Node, en
 			r.Writer.Write(w, n.Title)
 			_ = w.WriteByte('"')
 		}
+		if r.DownloadLinks != nil && r.DownloadLinks(destination) {
+			if _, ok := n.AttributeString("download"); !ok {
+				_, _ = w.WriteString(` download=""`)
+			}
+		}
 		if n.Attributes() != nil {
 			RenderAttributes(w, n, LinkAttributeFilter)
 		}
 		_ = w.WriteByte('>')
 	} else {
 		_, _ = w.WriteString("</a>")
+		if r.PrintURLs != nil && r.PrintURLs(destination) && (r.Unsafe || !IsDangerousURL(destination)) {
+			_, _ = w.WriteString(`<span class="print-url">`)
+			_, _ = w.Write(util.EscapeHTML(destination))
+			_, _ = w.WriteString("</span>")
+		}
 	}
 	return ast.WalkContinue, nil
 }
@@ -595,10 +1578,24 @@ func (r *Renderer) renderImage(w util.BufWriter, source []byte, node ast.Node, e
 		return ast.WalkContinue, nil
 	}
 	n := node.(*ast.Image)
-	_, _ = w.WriteString("<img src=\"")
-	if r.Unsafe || !IsDangerousURL(n.Destination) {
-		_, _ = w.Write(util.EscapeHTML(util.URLEscape(n.Destination, true)))
+	destination, ok := r.normalizeURL(n.Destination)
+	if ok && !r.Unsafe && IsDangerousURL(destination) {
+		if r.ImagePlaceholder == nil {
+			destination = nil
+		} else {
+			ok = false
+		}
+	}
+	if !ok {
+		if r.ImagePlaceholder != nil {
+			_, _ = w.Write(r.ImagePlaceholder)
+		} else {
+			_, _ = w.Write(nodeToHTMLText(n, source))
+		}
+		return ast.WalkSkipChildren, nil
 	}
+	_, _ = w.WriteString("<img src=\"")
+	_, _ = w.Write(util.EscapeHTML(util.URLEscape(destination, true)))
 	_, _ = w.WriteString(`" alt="`)
 	_, _ = w.Write(nodeToHTMLText(n, source))
 	_ = w.WriteByte('"')
@@ -607,6 +1604,13 @@ func (r *Renderer) renderImage(w util.BufWriter, source []byte, node ast.Node, e
 		r.Writer.Write(w, n.Title)
 		_ = w.WriteByte('"')
 	}
+	if r.ImageDecoding != nil {
+		if _, ok := n.AttributeString("decoding"); !ok {
+			_, _ = w.WriteString(` decoding="`)
+			_, _ = w.Write(util.EscapeHTML(r.ImageDecoding))
+			_ = w.WriteByte('"')
+		}
+	}
 	if n.Attributes() != nil {
 		RenderAttributes(w, n, ImageAttributeFilter)
 	}
@@ -635,6 +1639,23 @@ func (r *Renderer) renderRawHTML(w util.BufWriter, source []byte, node ast.Node,
 	return ast.WalkSkipChildren, nil
 }
 
+// isHardWrap returns true if the softline break of the given text should be
+// rendered as '<br>'.
+func (r *Renderer) isHardWrap(n ast.Node) bool {
+	if r.HardWraps {
+		return true
+	}
+	if !r.ParagraphHardWraps {
+		return false
+	}
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		if p.Type() == ast.TypeBlock {
+			return p.Kind() == ast.KindParagraph || p.Kind() == ast.KindTextBlock
+		}
+	}
+	return false
+}
+
 func (r *Renderer) renderText(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
 	if !entering {
 		return ast.WalkContinue, nil
@@ -646,7 +1667,7 @@ func (r *Renderer) renderText(w util.BufWriter, source []byte, node ast.Node, en
 	} else {
 		value := segment.Value(source)
 		r.Writer.Write(w, value)
-		if n.HardLineBreak() || (n.SoftLineBreak() && r.HardWraps) {
+		if n.HardLineBreak() || (n.SoftLineBreak() && r.isHardWrap(n)) {
 			if r.XHTML {
 				_, _ = w.WriteString("<br />\n")
 			} else {
@@ -711,6 +1732,76 @@ func RenderAttributes(w util.BufWriter, node ast.Node, filter util.BytesFilter)
 	}
 }
 
+var attrNameClass = []byte("class")
+
+// RenderAttributesWithClass renders given node's attributes like RenderAttributes
+// but appends the given class to the class attribute.
+// The class attribute of the node itself is not modified.
+func RenderAttributesWithClass(w util.BufWriter, node ast.Node, filter util.BytesFilter, class string) {
+	if _, ok := node.Attribute(attrNameClass); !ok {
+		_, _ = w.WriteString(` class="`)
+		_, _ = w.Write(util.EscapeHTML([]byte(class)))
+		_ = w.WriteByte('"')
+		RenderAttributes(w, node, filter)
+		return
+	}
+	renderAttributesWithAppendedValue(w, node, filter, attrNameClass, class, ' ')
+}
+
+var attrNameStyle = []byte("style")
+
+// RenderAttributesWithStyle renders given node's attributes like RenderAttributes
+// but appends the given declarations to the style attribute.
+// The style attribute of the node itself is not modified.
+func RenderAttributesWithStyle(w util.BufWriter, node ast.Node, filter util.BytesFilter, style string) {
+	if _, ok := node.Attribute(attrNameStyle); !ok {
+		RenderAttributes(w, node, filter)
+		_, _ = w.WriteString(` style="`)
+		_, _ = w.Write(util.EscapeHTML([]byte(style)))
+		_ = w.WriteByte('"')
+		return
+	}
+	renderAttributesWithAppendedValue(w, node, filter, attrNameStyle, style, ';')
+}
+
+// renderAttributesWithAppendedValue renders given node's attributes with
+// appending the given value to the attribute that has the given name.
+// The values are separated by sep.
+func renderAttributesWithAppendedValue(w util.BufWriter, node ast.Node, filter util.BytesFilter,
+	name []byte, appended string, sep byte) {
+	for _, attr := range node.Attributes() {
+		if bytes.Equal(attr.Name, name) {
+			var value []byte
+			switch v := attr.Value.(type) {
+			case []byte:
+				value = v
+			case string:
+				value = util.StringToReadOnlyBytes(v)
+			}
+			_ = w.WriteByte(' ')
+			_, _ = w.Write(name)
+			_, _ = w.WriteString(`="`)
+			if len(value) != 0 {
+				_, _ = w.Write(util.EscapeHTML(value))
+				_ = w.WriteByte(sep)
+			}
+			_, _ = w.Write(util.EscapeHTML([]byte(appended)))
+			_ = w.WriteByte('"')
+			continue
+		}
+		if filter != nil && !filter.Contains(attr.Name) {
+			if !bytes.HasPrefix(attr.Name, dataPrefix) {
+				continue
+			}
+		}
+		_, _ = w.WriteString(" ")
+		_, _ = w.Write(attr.Name)
+		_, _ = w.WriteString(`="`)
+		_, _ = w.Write(util.EscapeHTML(attr.Value.([]byte)))
+		_ = w.WriteByte('"')
+	}
+}
+
 // A Writer interface writes textual contents to a writer.
 type Writer interface {
 	// Write writes the given source to writer with resolving references and unescaping
@@ -0,0 +1,852 @@
+// Package markdown implements renderer that outputs CommonMark.
+package markdown
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// A Config struct has configurations for the Markdown based renderers.
+type Config struct {
+	// BulletChar is a marker character of bullet lists.
+	// This is one of '-', '+' and '*'.
+	BulletChar byte
+
+	// IndentWidth is a width of indentation of list item contents.
+	// Contents are indented by at least the width of the list marker and a
+	// space, and at most by the width of the list marker and 4 spaces.
+	IndentWidth int
+
+	// LazyOrderedNumbers indicates that ordered list items except the first
+	// one are rendered as '1.' to minimize diffs. The first item keeps the
+	// start number of the list.
+	LazyOrderedNumbers bool
+
+	// ExtractReferences indicates that destinations of links and images
+	// used 2 or more times are rendered as link reference definitions at
+	// the end of the document, and the links and images refer to them.
+	ExtractReferences bool
+}
+
+// NewConfig returns a new Config with defaults.
+func NewConfig() Config {
+	return Config{
+		BulletChar:         '-',
+		IndentWidth:        2,
+		LazyOrderedNumbers: false,
+		ExtractReferences:  false,
+	}
+}
+
+// SetOption implements renderer.SetOptioner.
+func (c *Config) SetOption(name renderer.OptionName, value interface{}) {
+	switch name {
+	case optBulletChar:
+		c.BulletChar = value.(byte)
+	case optIndentWidth:
+		c.IndentWidth = value.(int)
+	case optLazyOrderedNumbers:
+		c.LazyOrderedNumbers = value.(bool)
+	case optExtractReferences:
+		c.ExtractReferences = value.(bool)
+	}
+}
+
+// An Option interface sets options for Markdown based renderers.
+type Option interface {
+	SetMarkdownOption(*Config)
+}
+
+// BulletChar is an option name used in WithBulletChar.
+const optBulletChar renderer.OptionName = "MarkdownBulletChar"
+
+type withBulletChar struct {
+	value byte
+}
+
+func (o *withBulletChar) SetConfig(c *renderer.Config) {
+	c.Options[optBulletChar] = o.value
+}
+
+func (o *withBulletChar) SetMarkdownOption(c *Config) {
+	c.BulletChar = o.value
+}
+
+// WithBulletChar is a functional option that sets a marker character
+// (one of '-', '+' and '*') of bullet lists.
+func WithBulletChar(value byte) interface {
+	renderer.Option
+	Option
+} {
+	return &withBulletChar{value}
+}
+
+// IndentWidth is an option name used in WithIndentWidth.
+const optIndentWidth renderer.OptionName = "MarkdownIndentWidth"
+
+type withIndentWidth struct {
+	value int
+}
+
+func (o *withIndentWidth) SetConfig(c *renderer.Config) {
+	c.Options[optIndentWidth] = o.value
+}
+
+func (o *withIndentWidth) SetMarkdownOption(c *Config) {
+	c.IndentWidth = o.value
+}
+
+// WithIndentWidth is a functional option that sets a width of indentation
+// of list item contents.
+func WithIndentWidth(value int) interface {
+	renderer.Option
+	Option
+} {
+	return &withIndentWidth{value}
+}
+
+// LazyOrderedNumbers is an option name used in WithLazyOrderedNumbers.
+const optLazyOrderedNumbers renderer.OptionName = "MarkdownLazyOrderedNumbers"
+
+type withLazyOrderedNumbers struct {
+}
+
+func (o *withLazyOrderedNumbers) SetConfig(c *renderer.Config) {
+	c.Options[optLazyOrderedNumbers] = true
+}
+
+func (o *withLazyOrderedNumbers) SetMarkdownOption(c *Config) {
+	c.LazyOrderedNumbers = true
+}
+
+// WithLazyOrderedNumbers is a functional option that renders ordered list
+// items except the first one as '1.'.
+func WithLazyOrderedNumbers() interface {
+	renderer.Option
+	Option
+} {
+	return &withLazyOrderedNumbers{}
+}
+
+// ExtractReferences is an option name used in WithExtractReferences.
+const optExtractReferences renderer.OptionName = "MarkdownExtractReferences"
+
+type withExtractReferences struct {
+}
+
+func (o *withExtractReferences) SetConfig(c *renderer.Config) {
+	c.Options[optExtractReferences] = true
+}
+
+func (o *withExtractReferences) SetMarkdownOption(c *Config) {
+	c.ExtractReferences = true
+}
+
+// WithExtractReferences is a functional option that renders destinations
+// of links and images used 2 or more times as link reference definitions
+// like '[1]: /url' and renders the links as '[text][1]'.
+func WithExtractReferences() interface {
+	renderer.Option
+	Option
+} {
+	return &withExtractReferences{}
+}
+
+// A Renderer struct is an implementation of renderer.Renderer that renders
+// nodes as CommonMark.
+// Renderer does not use renderer.NodeRenderers, so nodes that are
+// not defined in the CommonMark are rendered as their children.
+type Renderer struct {
+	Config
+}
+
+// NewRenderer returns a new Renderer with given options.
+func NewRenderer(opts ...Option) renderer.Renderer {
+	r := &Renderer{
+		Config: NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetMarkdownOption(&r.Config)
+	}
+	return r
+}
+
+// AddOptions implements renderer.Renderer.AddOptions.
+func (r *Renderer) AddOptions(opts ...renderer.Option) {
+	config := renderer.NewConfig()
+	for _, opt := range opts {
+		opt.SetConfig(config)
+	}
+	for name, value := range config.Options {
+		r.SetOption(name, value)
+	}
+}
+
+// Render implements renderer.Renderer.Render.
+func (r *Renderer) Render(w io.Writer, source []byte, n ast.Node) error {
+	writer, ok := w.(util.BufWriter)
+	if !ok {
+		writer = bufio.NewWriter(w)
+	}
+	mw := &markdownWriter{w: writer, lineHead: true}
+	if r.ExtractReferences && n.Kind() == ast.KindDocument {
+		mw.references, mw.definitions = collectReferences(n)
+	}
+	if n.Type() == ast.TypeInline {
+		r.renderInline(mw, source, n)
+	} else {
+		r.renderBlock(mw, source, n)
+	}
+	if len(mw.definitions) != 0 {
+		mw.writeByte('\n')
+		for i, ref := range mw.definitions {
+			mw.writeString("[" + strconv.Itoa(i+1) + "]: ")
+			writeLinkDestination(mw, []byte(ref.destination), ref.titleBytes())
+			mw.writeByte('\n')
+		}
+	}
+	return writer.Flush()
+}
+
+// referenceKey identifies a link reference definition extracted by the
+// ExtractReferences option.
+type referenceKey struct {
+	destination string
+	title       string
+	hasTitle    bool
+}
+
+func newReferenceKey(destination, title []byte) referenceKey {
+	return referenceKey{
+		destination: string(destination),
+		title:       string(title),
+		hasTitle:    title != nil,
+	}
+}
+
+func (k referenceKey) titleBytes() []byte {
+	if !k.hasTitle {
+		return nil
+	}
+	return []byte(k.title)
+}
+
+// collectReferences returns labels of links and images whose destinations
+// and titles are used 2 or more times in the given document, and the
+// references in order of their first appearances.
+func collectReferences(doc ast.Node) (map[referenceKey]int, []referenceKey) {
+	counts := map[referenceKey]int{}
+	var order []referenceKey
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		var key referenceKey
+		switch n := n.(type) {
+		case *ast.Link:
+			key = newReferenceKey(n.Destination, n.Title)
+		case *ast.Image:
+			key = newReferenceKey(n.Destination, n.Title)
+		default:
+			return ast.WalkContinue, nil
+		}
+		if counts[key] == 0 {
+			order = append(order, key)
+		}
+		counts[key]++
+		return ast.WalkContinue, nil
+	})
+	references := map[referenceKey]int{}
+	var definitions []referenceKey
+	for _, key := range order {
+		if counts[key] < 2 {
+			continue
+		}
+		definitions = append(definitions, key)
+		references[key] = len(definitions)
+	}
+	return references, definitions
+}
+
+type linePrefix struct {
+	first []byte
+	rest  []byte
+	used  bool
+}
+
+// markdownWriter writes line prefixes of container blocks
+// like '> ' at the beginning of lines.
+type markdownWriter struct {
+	w        io.Writer
+	prefixes []*linePrefix
+	lineHead bool
+
+	// references and definitions are link reference definitions
+	// extracted by the ExtractReferences option.
+	references  map[referenceKey]int
+	definitions []referenceKey
+}
+
+func (w *markdownWriter) pushPrefix(first, rest []byte) {
+	w.prefixes = append(w.prefixes, &linePrefix{first: first, rest: rest})
+}
+
+func (w *markdownWriter) popPrefix() {
+	w.prefixes = w.prefixes[:len(w.prefixes)-1]
+}
+
+func (w *markdownWriter) writePrefix(blank bool) {
+	var buf []byte
+	for _, p := range w.prefixes {
+		if p.used {
+			buf = append(buf, p.rest...)
+		} else {
+			buf = append(buf, p.first...)
+			p.used = true
+		}
+	}
+	if blank {
+		buf = util.TrimRightSpace(buf)
+	}
+	_, _ = w.w.Write(buf)
+}
+
+func (w *markdownWriter) write(b []byte) {
+	for len(b) > 0 {
+		if w.lineHead {
+			w.writePrefix(b[0] == '\n')
+			w.lineHead = false
+		}
+		i := bytes.IndexByte(b, '\n')
+		if i < 0 {
+			_, _ = w.w.Write(b)
+			return
+		}
+		_, _ = w.w.Write(b[:i+1])
+		w.lineHead = true
+		b = b[i+1:]
+	}
+}
+
+func (w *markdownWriter) writeString(s string) {
+	w.write([]byte(s))
+}
+
+func (w *markdownWriter) writeByte(c byte) {
+	w.write([]byte{c})
+}
+
+func (r *Renderer) renderBlock(w *markdownWriter, source []byte, n ast.Node) {
+	switch n := n.(type) {
+	case *ast.Document:
+		_ = r.renderBlocks(w, source, n)
+	case *ast.Paragraph, *ast.TextBlock:
+		r.renderInlines(w, source, n)
+		w.writeByte('\n')
+	case *ast.Heading:
+		r.renderHeading(w, source, n)
+	case *ast.ThematicBreak:
+		// '___' can not be a setext heading underline nor a list item.
+		w.writeString("___\n")
+	case *ast.CodeBlock:
+		// indented code blocks that follow lists would be parsed as
+		// contents of the list items.
+		if n.PreviousSibling() != nil && n.PreviousSibling().Kind() == ast.KindList {
+			r.renderFencedCodeBlock(w, source, n, nil)
+		} else {
+			r.renderCodeBlock(w, source, n)
+		}
+	case *ast.FencedCodeBlock:
+		var info []byte
+		if n.Info != nil {
+			info = n.Info.Segment.Value(source)
+		}
+		r.renderFencedCodeBlock(w, source, n, info)
+	case *ast.Blockquote:
+		w.pushPrefix([]byte("> "), []byte("> "))
+		if r.renderBlocks(w, source, n) == 0 {
+			w.writeByte('\n')
+		}
+		w.popPrefix()
+	case *ast.List:
+		r.renderList(w, source, n)
+	case *ast.HTMLBlock:
+		r.writeLines(w, source, n)
+		if n.HasClosure() {
+			w.write(n.ClosureLine.Value(source))
+		}
+	default:
+		_ = r.renderBlocks(w, source, n)
+	}
+}
+
+// isTight returns true if children of the given node must not be
+// separated by blank lines.
+func isTight(n ast.Node) bool {
+	if l, ok := n.(*ast.List); ok {
+		return l.IsTight
+	}
+	if _, ok := n.(*ast.ListItem); ok {
+		if l, ok := n.Parent().(*ast.List); ok {
+			return l.IsTight
+		}
+	}
+	return false
+}
+
+func (r *Renderer) renderBlocks(w *markdownWriter, source []byte, n ast.Node) int {
+	tight := isTight(n)
+	rendered := 0
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		// paragraphs that consist of link reference definitions remain
+		// as empty text blocks.
+		if c.Kind() == ast.KindTextBlock && !c.HasChildren() {
+			continue
+		}
+		if rendered != 0 && !tight {
+			w.writeByte('\n')
+		}
+		rendered++
+		if c.Type() == ast.TypeInline {
+			r.renderInline(w, source, c)
+			w.writeByte('\n')
+			continue
+		}
+		r.renderBlock(w, source, c)
+	}
+	return rendered
+}
+
+func (r *Renderer) renderHeading(w *markdownWriter, source []byte, n *ast.Heading) {
+	hasLineBreak := false
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if t, ok := c.(*ast.Text); ok && entering && (t.SoftLineBreak() || t.HardLineBreak()) {
+			hasLineBreak = true
+			return ast.WalkStop, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	// ATX headings can not have line breaks.
+	if hasLineBreak && n.Level <= 2 {
+		r.renderInlines(w, source, n)
+		if n.Level == 1 {
+			w.writeString("\n===\n")
+		} else {
+			w.writeString("\n---\n")
+		}
+		return
+	}
+	var buf bytes.Buffer
+	hw := &markdownWriter{w: &buf}
+	r.renderInlines(hw, source, n)
+	value := bytes.ReplaceAll(buf.Bytes(), []byte("\\\n"), []byte(" "))
+	value = bytes.ReplaceAll(value, []byte("\n"), []byte(" "))
+	for i := 0; i < n.Level; i++ {
+		w.writeByte('#')
+	}
+	if len(value) != 0 {
+		w.writeByte(' ')
+		w.write(value)
+		// keep trailing '#'s that would be a closing sequence.
+		if l := len(value); value[l-1] == '#' && (l < 2 || value[l-2] != '\\') {
+			w.writeString(" #")
+		}
+	}
+	w.writeByte('\n')
+}
+
+func (r *Renderer) writeLines(w *markdownWriter, source []byte, n ast.Node) {
+	l := n.Lines().Len()
+	for i := 0; i < l; i++ {
+		line := n.Lines().At(i)
+		w.write(line.Value(source))
+	}
+}
+
+func (r *Renderer) renderCodeBlock(w *markdownWriter, source []byte, n *ast.CodeBlock) {
+	l := n.Lines().Len()
+	for i := 0; i < l; i++ {
+		line := n.Lines().At(i)
+		value := line.Value(source)
+		if value[0] != '\n' {
+			w.writeString("    ")
+		}
+		w.write(value)
+	}
+}
+
+func (r *Renderer) renderFencedCodeBlock(w *markdownWriter, source []byte, n ast.Node, info []byte) {
+	fenceChar := byte('`')
+	if bytes.IndexByte(info, '`') > -1 {
+		fenceChar = '~'
+	}
+	fenceLength := 3
+	l := n.Lines().Len()
+	for i := 0; i < l; i++ {
+		line := n.Lines().At(i)
+		value := util.TrimLeftSpace(line.Value(source))
+		j := 0
+		for ; j < len(value) && value[j] == fenceChar; j++ {
+		}
+		if j >= fenceLength {
+			fenceLength = j + 1
+		}
+	}
+	fence := bytes.Repeat([]byte{fenceChar}, fenceLength)
+	w.write(fence)
+	w.write(info)
+	w.writeByte('\n')
+	r.writeLines(w, source, n)
+	w.write(fence)
+	w.writeByte('\n')
+}
+
+// listMarker returns a marker character of the given list.
+// Adjacent lists that have the same marker are merged into one list,
+// so markers of adjacent lists are alternated.
+func (r *Renderer) listMarker(n *ast.List) byte {
+	var marker, alt byte
+	if n.IsOrdered() {
+		marker, alt = '.', ')'
+	} else {
+		marker = r.BulletChar
+		if marker != '-' && marker != '+' && marker != '*' {
+			marker = '-'
+		}
+		alt = '*'
+		if marker == '*' {
+			alt = '-'
+		}
+	}
+	if prev, ok := n.PreviousSibling().(*ast.List); ok && prev.IsOrdered() == n.IsOrdered() && r.listMarker(prev) == marker {
+		return alt
+	}
+	return marker
+}
+
+func (r *Renderer) renderList(w *markdownWriter, source []byte, n *ast.List) {
+	marker := r.listMarker(n)
+	number := n.Start
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if c.PreviousSibling() != nil && !n.IsTight {
+			w.writeByte('\n')
+		}
+		var first []byte
+		if n.IsOrdered() {
+			first = strconv.AppendInt(first, int64(number), 10)
+			number++
+			if r.LazyOrderedNumbers {
+				number = 1
+			}
+		}
+		first = append(first, marker)
+		width := r.IndentWidth
+		// list items that start with 5 or more spaces after the marker
+		// start with indented code blocks.
+		if fc := c.FirstChild(); fc != nil && fc.Kind() == ast.KindCodeBlock {
+			width = 0
+		}
+		if width < len(first)+1 {
+			width = len(first) + 1
+		} else if width > len(first)+4 {
+			width = len(first) + 4
+		}
+		for len(first) < width {
+			first = append(first, ' ')
+		}
+		w.pushPrefix(first, bytes.Repeat([]byte{' '}, width))
+		if r.renderBlocks(w, source, c) == 0 {
+			w.writeByte('\n')
+		}
+		w.popPrefix()
+	}
+}
+
+func (r *Renderer) renderInlines(w *markdownWriter, source []byte, n ast.Node) {
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		r.renderInline(w, source, c)
+	}
+}
+
+func (r *Renderer) renderInline(w *markdownWriter, source []byte, n ast.Node) {
+	switch n := n.(type) {
+	case *ast.Text:
+		r.renderText(w, source, n)
+	case *ast.String:
+		if n.IsRaw() || n.IsCode() {
+			w.write(n.Value)
+		} else {
+			writeEscapedText(w, n.Value, ' ', ' ')
+		}
+	case *ast.CodeSpan:
+		r.renderCodeSpan(w, source, n)
+	case *ast.Emphasis:
+		marker := bytes.Repeat([]byte{emphasisMarker(n)}, n.Level)
+		w.write(marker)
+		r.renderInlines(w, source, n)
+		w.write(marker)
+	case *ast.Link:
+		w.writeByte('[')
+		r.renderInlines(w, source, n)
+		writeLinkTarget(w, n.Destination, n.Title)
+	case *ast.Image:
+		w.writeString("![")
+		r.renderInlines(w, source, n)
+		writeLinkTarget(w, n.Destination, n.Title)
+	case *ast.AutoLink:
+		label := n.Label(source)
+		// extended www autolinks do not have a scheme and can not be
+		// enclosed with '<' and '>'.
+		if n.AutoLinkType == ast.AutoLinkEmail || hasScheme(label) {
+			w.writeByte('<')
+			w.write(label)
+			w.writeByte('>')
+		} else {
+			w.write(label)
+		}
+	case *ast.RawHTML:
+		l := n.Segments.Len()
+		for i := 0; i < l; i++ {
+			segment := n.Segments.At(i)
+			w.write(segment.Value(source))
+		}
+	default:
+		r.renderInlines(w, source, n)
+	}
+}
+
+func (r *Renderer) renderText(w *markdownWriter, source []byte, n *ast.Text) {
+	segment := n.Segment
+	value := segment.Value(source)
+	if n.IsRaw() {
+		w.write(value)
+	} else {
+		prev, next := byte('\n'), byte('\n')
+		if segment.Start > 0 {
+			prev = source[segment.Start-1]
+		}
+		if segment.Stop < len(source) {
+			next = source[segment.Stop]
+		}
+		writeEscapedText(w, value, prev, next)
+	}
+	if n.HardLineBreak() {
+		w.writeString("\\\n")
+	} else if n.SoftLineBreak() {
+		w.writeByte('\n')
+	}
+}
+
+// emphasisMarker returns a delimiter character of the given emphasis.
+// Emphasis that is the only child of another emphasis uses a different
+// delimiter character because '**x**' and '***x***' would be parsed
+// as another structure.
+func emphasisMarker(n *ast.Emphasis) byte {
+	if parent, ok := n.Parent().(*ast.Emphasis); ok && n.Level == 1 &&
+		parent.FirstChild() == n && parent.LastChild() == n {
+		if emphasisMarker(parent) == '*' {
+			return '_'
+		}
+	}
+	return '*'
+}
+
+func hasScheme(value []byte) bool {
+	i := bytes.IndexByte(value, ':')
+	if i < 2 || i > 32 || !util.IsAlphaNumeric(value[0]) {
+		return false
+	}
+	for _, c := range value[:i] {
+		if !util.IsAlphaNumeric(c) && c != '+' && c != '.' && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Renderer) renderCodeSpan(w *markdownWriter, source []byte, n *ast.CodeSpan) {
+	var buf []byte
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		var value []byte
+		switch v := c.(type) {
+		case *ast.Text:
+			value = v.Segment.Value(source)
+		case *ast.String:
+			value = v.Value
+		}
+		buf = append(buf, value...)
+	}
+	buf = bytes.ReplaceAll(buf, []byte("\n"), []byte(" "))
+	longest := 0
+	for i := 0; i < len(buf); {
+		j := i
+		for ; j < len(buf) && buf[j] == '`'; j++ {
+		}
+		if j-i > longest {
+			longest = j - i
+		}
+		if j == i {
+			j++
+		}
+		i = j
+	}
+	fence := bytes.Repeat([]byte{'`'}, longest+1)
+	// a leading and trailing space are stripped by the CommonMark rule.
+	pad := len(buf) != 0 && (buf[0] == '`' || buf[len(buf)-1] == '`' ||
+		(buf[0] == ' ' && buf[len(buf)-1] == ' ' && !util.IsBlank(buf)))
+	w.write(fence)
+	if pad {
+		w.writeByte(' ')
+	}
+	w.write(buf)
+	if pad {
+		w.writeByte(' ')
+	}
+	w.write(fence)
+}
+
+// writeLinkTarget writes the rest of a link or an image after its text
+// like '](/url "title")' or '][1]' if the destination is extracted as a
+// link reference definition.
+func writeLinkTarget(w *markdownWriter, destination, title []byte) {
+	if label, ok := w.references[newReferenceKey(destination, title)]; ok {
+		w.writeString("][" + strconv.Itoa(label) + "]")
+		return
+	}
+	w.writeString("](")
+	writeLinkDestination(w, destination, title)
+	w.writeByte(')')
+}
+
+func writeLinkDestination(w *markdownWriter, destination, title []byte) {
+	opened := 0
+	balanced := true
+	angle := len(destination) == 0 || destination[0] == '<'
+	for i := 0; i < len(destination); i++ {
+		c := destination[i]
+		if c == '\\' && i < len(destination)-1 && util.IsPunct(destination[i+1]) {
+			i++
+			continue
+		}
+		if c == '(' {
+			opened++
+		} else if c == ')' {
+			opened--
+			if opened < 0 {
+				balanced = false
+			}
+		} else if util.IsSpace(c) {
+			angle = true
+		}
+	}
+	if angle || !balanced || opened != 0 {
+		w.writeByte('<')
+		w.write(destination)
+		w.writeByte('>')
+	} else {
+		w.write(destination)
+	}
+	if title != nil {
+		w.writeString(` "`)
+		for i := 0; i < len(title); i++ {
+			c := title[i]
+			if c == '\\' && i < len(title)-1 && util.IsPunct(title[i+1]) {
+				w.write(title[i : i+2])
+				i++
+				continue
+			}
+			if c == '"' {
+				w.writeByte('\\')
+			}
+			w.writeByte(c)
+		}
+		w.writeByte('"')
+	}
+}
+
+// blockStartEscapePosition returns a position of a character that should
+// be escaped to prevent the given text at the beginning of a line from
+// being parsed as a block. This returns -1 if no characters should be
+// escaped.
+func blockStartEscapePosition(value []byte) int {
+	if len(value) == 0 {
+		return -1
+	}
+	next := byte('\n')
+	if len(value) > 1 {
+		next = value[1]
+	}
+	switch c := value[0]; c {
+	case '>':
+		return 0
+	case '#':
+		if util.IsSpace(next) || next == '#' {
+			return 0
+		}
+	case '-', '+', '=':
+		if util.IsSpace(next) || next == c {
+			return 0
+		}
+	case '`', '~':
+		if bytes.HasPrefix(value, []byte{c, c, c}) {
+			return 0
+		}
+	case '<':
+		if util.IsAlphaNumeric(next) || next == '/' || next == '!' || next == '?' {
+			return 0
+		}
+	default:
+		i := 0
+		for ; i < len(value) && i < 10 && util.IsNumeric(value[i]); i++ {
+		}
+		if i != 0 && i < 10 && i < len(value) && (value[i] == '.' || value[i] == ')') &&
+			(i == len(value)-1 || util.IsSpace(value[i+1])) {
+			return i
+		}
+	}
+	return -1
+}
+
+// writeEscapedText writes the given text with escaping characters that
+// would be parsed as markups. prev and next are characters around the text.
+func writeEscapedText(w *markdownWriter, value []byte, prev, next byte) {
+	escapeAt := -1
+	if w.lineHead {
+		escapeAt = blockStartEscapePosition(value)
+	}
+	start := 0
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c == '\\' && i < len(value)-1 && util.IsPunct(value[i+1]) {
+			i++
+			continue
+		}
+		escape := i == escapeAt
+		switch c {
+		case '*', '[', ']', '`':
+			escape = true
+		case '_':
+			p, n := prev, next
+			if i > 0 {
+				p = value[i-1]
+			}
+			if i < len(value)-1 {
+				n = value[i+1]
+			}
+			// intraword '_'s can not be delimiters.
+			escape = escape || !util.IsAlphaNumeric(p) || !util.IsAlphaNumeric(n)
+		}
+		if escape {
+			w.write(value[start:i])
+			w.writeByte('\\')
+			start = i
+		}
+	}
+	w.write(value[start:])
+}
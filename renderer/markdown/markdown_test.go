@@ -0,0 +1,321 @@
+package markdown_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/renderer/html"
+	. "github.com/yuin/goldmark/renderer/markdown"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestRenderer(t *testing.T) {
+	markdown := goldmark.New(goldmark.WithRenderer(NewRenderer()))
+	cases := []testutil.MarkdownTestCase{
+		{
+			No:          1,
+			Description: "Headings are rendered as ATX headings if possible",
+			Markdown: `Setext *heading*
+===
+
+Multi
+line
+---
+
+## ATX heading ##
+
+### Heading \#
+
+Trailing #
+---
+`,
+			Expected: `# Setext *heading*
+
+Multi
+line
+---
+
+## ATX heading
+
+### Heading \#
+
+## Trailing # #`,
+		},
+		{
+			No:          2,
+			Description: "Inlines",
+			Markdown: `*em* _em_ **strong** __strong__ *_both_* ***both***
+` + "`code` `` a`b `` ``` `` ```" + `
+[link](/url 'title "quoted"') [ref] ![image](</my image.png>)
+<http://example.com> <foo@example.com> <span>raw</span>
+hard  
+break\
+and soft
+break
+
+[ref]: /ref
+`,
+			Expected: `*em* *em* **strong** **strong** *_both_* ***both***
+` + "`code` ``a`b`` ``` `` ```" + `
+[link](/url "title \"quoted\"") [ref](/ref) ![image](</my image.png>)
+<http://example.com> <foo@example.com> <span>raw</span>
+hard\
+break\
+and soft
+break`,
+		},
+		{
+			No:          3,
+			Description: "Literal characters are escaped",
+			Markdown: `\*not em\* 2 * 3 snake_case _ [brackets] ` + "\\`" + `
+    - not a list
+    1. not a list
+    # not a heading
+    ---`,
+			Expected: `\*not em\* 2 \* 3 snake_case \_ \[brackets\] ` + "\\`" + `
+\- not a list
+1\. not a list
+\# not a heading
+\---`,
+		},
+		{
+			No:          4,
+			Description: "Lists",
+			Markdown: `* a
+* b
+    + nested
+
+      loose
+
+3) c
+4) d
+
+- e
+
+- f
+`,
+			Expected: `- a
+- b
+  - nested
+
+    loose
+
+3. c
+4. d
+
+- e
+
+- f`,
+		},
+		{
+			No:          5,
+			Description: "Adjacent lists use different markers",
+			Markdown: `- a
++ b
+
+1. c
+2) d
+`,
+			Expected: `- a
+
+* b
+
+1. c
+
+2) d`,
+		},
+		{
+			No:          6,
+			Description: "Code blocks",
+			Markdown:    "```go {title=x}\n````` not a fence\n```\n\n~~~ `info`\n~~~\n\n    indented\n      code\n\n -    item\n\n     after a list\n",
+			Expected:    "``````go {title=x}\n````` not a fence\n``````\n\n~~~`info`\n~~~\n\n    indented\n      code\n\n- item\n\n```\n after a list\n```",
+		},
+		{
+			No:          7,
+			Description: "Blockquotes and thematic breaks",
+			Markdown: `> quote
+lazy
+> > nested
+>
+> - a
+> - b
+
+***
+`,
+			Expected: `> quote
+> lazy
+>
+> > nested
+>
+> - a
+> - b
+
+___`,
+		},
+	}
+	for _, c := range cases {
+		testutil.DoTestCase(markdown, c, t)
+	}
+}
+
+func TestRendererOptions(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRenderer(NewRenderer(
+			WithBulletChar('*'),
+		)),
+		goldmark.WithRendererOptions(
+			WithIndentWidth(4),
+		),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Bullet char and indent width",
+			Markdown: `- a
+- b
+  - nested
+
+10. ten
+11. eleven
+
+-     code
+`,
+			Expected: `*   a
+*   b
+    *   nested
+
+10. ten
+11. eleven
+
+*     code`,
+		},
+		t,
+	)
+}
+
+func TestLazyOrderedNumbers(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRenderer(NewRenderer(
+			WithLazyOrderedNumbers(),
+		)),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Ordered list items except the first one are rendered as 1.",
+			Markdown: `3. a
+4. b
+   1. nested
+   2. nested
+5. c
+
+- d
+`,
+			Expected: `3. a
+1. b
+   1. nested
+   1. nested
+1. c
+
+- d`,
+		},
+		t,
+	)
+}
+
+func TestExtractReferences(t *testing.T) {
+	markdown := goldmark.New(
+		goldmark.WithRenderer(NewRenderer(
+			WithExtractReferences(),
+		)),
+	)
+	testutil.DoTestCase(
+		markdown,
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Destinations used 2 or more times are extracted as reference definitions",
+			Markdown: `[a](https://example.com) [b](/once) [c](/t "title")
+
+> [d](https://example.com) ![e](https://example.com) [f](/t "title") [g](/t)
+`,
+			Expected: `[a][1] [b](/once) [c][2]
+
+> [d][1] ![e][1] [f][2] [g](/t)
+
+[1]: https://example.com
+[2]: /t "title"`,
+		},
+		t,
+	)
+
+	var buf bytes.Buffer
+	source := []byte("[a](</x y>) ![b](</x y>)\n")
+	if err := markdown.Convert(source, &buf); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := goldmark.New().Convert(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	expected := `<p><a href="/x%20y">a</a> <img src="/x%20y" alt="b"></p>` + "\n"
+	if out.String() != expected {
+		t.Errorf("extracted references should be parsed as the original links, but got %q from %q", out.String(), buf.String())
+	}
+}
+
+type commonmarkSpecTestCase struct {
+	Markdown string `json:"markdown"`
+	Example  int    `json:"example"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	bs, err := ioutil.ReadFile("../../_test/spec.json")
+	if err != nil {
+		panic(err)
+	}
+	var testCases []commonmarkSpecTestCase
+	if err := json.Unmarshal(bs, &testCases); err != nil {
+		panic(err)
+	}
+	renderers := []goldmark.Markdown{
+		goldmark.New(goldmark.WithRenderer(NewRenderer())),
+		goldmark.New(goldmark.WithRenderer(NewRenderer(WithBulletChar('+'), WithIndentWidth(4)))),
+		goldmark.New(goldmark.WithRenderer(NewRenderer(WithLazyOrderedNumbers()))),
+	}
+	htmlRenderer := goldmark.New(goldmark.WithRendererOptions(html.WithUnsafe()))
+	for _, c := range testCases {
+		// goldmark renders alt texts of images without unescaping
+		// backslash escapes, so escaped brackets are rendered as they are.
+		if c.Example == 519 {
+			continue
+		}
+		var expected bytes.Buffer
+		if err := htmlRenderer.Convert([]byte(c.Markdown), &expected); err != nil {
+			t.Fatal(err)
+		}
+		for _, renderer := range renderers {
+			var md, md2, actual bytes.Buffer
+			if err := renderer.Convert([]byte(c.Markdown), &md); err != nil {
+				t.Fatal(err)
+			}
+			if err := htmlRenderer.Convert(md.Bytes(), &actual); err != nil {
+				t.Fatal(err)
+			}
+			if expected.String() != actual.String() {
+				t.Errorf("example %d: rendered markdown is not equivalent:\n%s\nexpected:\n%s\nactual:\n%s",
+					c.Example, md.String(), expected.String(), actual.String())
+			}
+			if err := renderer.Convert(md.Bytes(), &md2); err != nil {
+				t.Fatal(err)
+			}
+			if md.String() != md2.String() {
+				t.Errorf("example %d: rendered markdown is not stable:\n%s\n%s", c.Example, md.String(), md2.String())
+			}
+		}
+	}
+}
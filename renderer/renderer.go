@@ -61,6 +61,42 @@ func WithOption(name OptionName, value interface{}) Option {
 	return &withOption{name, value}
 }
 
+// MaxNodes is an option name used in WithMaxNodes.
+const optMaxNodes OptionName = "MaxNodes"
+
+// WithMaxNodes is a functional option that limits the number of nodes
+// rendered under the given root node. Nodes beyond the limit are not
+// rendered and a truncation notice is written at the end of the output.
+// A value less than or equal to 0 means unlimited.
+func WithMaxNodes(n int) Option {
+	return WithOption(optMaxNodes, n)
+}
+
+// TruncationNotice is an option name used in WithTruncationNotice.
+const optTruncationNotice OptionName = "TruncationNotice"
+
+// WithTruncationNotice is a functional option that sets a notice written
+// when the output is truncated by WithMaxNodes.
+// The notice is written as is. The default notice is "…\n".
+func WithTruncationNotice(notice string) Option {
+	return WithOption(optTruncationNotice, []byte(notice))
+}
+
+var defaultTruncationNotice = []byte("…\n")
+
+// BufferSize is an option name used in WithBufferSize.
+const optBufferSize OptionName = "BufferSize"
+
+// WithBufferSize is a functional option that sets the size of the buffer
+// used to write rendered contents to writers that are not util.BufWriter.
+// The Renderer flushes the buffer to the writer whenever it becomes full,
+// so the memory used for the output is bounded by this size regardless of
+// the size of documents.
+// A value less than or equal to 0 means the default size of bufio.Writer.
+func WithBufferSize(n int) Option {
+	return WithOption(optBufferSize, n)
+}
+
 // A SetOptioner interface sets given option to the object.
 type SetOptioner interface {
 	// SetOption sets given option to the object.
@@ -99,6 +135,9 @@ type renderer struct {
 	nodeRendererFuncsTmp map[ast.NodeKind]NodeRendererFunc
 	maxKind              int
 	nodeRendererFuncs    []NodeRendererFunc
+	maxNodes             int
+	truncationNotice     []byte
+	bufferSize           int
 	initSync             sync.Once
 }
 
@@ -132,9 +171,21 @@ func (r *renderer) Register(kind ast.NodeKind, v NodeRendererFunc) {
 }
 
 // Render renders the given AST node to the given writer with the given Renderer.
+// Rendered contents are written to the writer incrementally while walking
+// the AST, thus the whole output is never held in memory.
 func (r *renderer) Render(w io.Writer, source []byte, n ast.Node) error {
 	r.initSync.Do(func() {
 		r.options = r.config.Options
+		if v, ok := r.options[optMaxNodes]; ok {
+			r.maxNodes = v.(int)
+		}
+		if v, ok := r.options[optBufferSize]; ok {
+			r.bufferSize = v.(int)
+		}
+		r.truncationNotice = defaultTruncationNotice
+		if v, ok := r.options[optTruncationNotice]; ok {
+			r.truncationNotice = v.([]byte)
+		}
 		r.config.NodeRenderers.Sort()
 		l := len(r.config.NodeRenderers)
 		for i := l - 1; i >= 0; i-- {
@@ -156,9 +207,30 @@ func (r *renderer) Render(w io.Writer, source []byte, n ast.Node) error {
 	})
 	writer, ok := w.(util.BufWriter)
 	if !ok {
-		writer = bufio.NewWriter(w)
+		if r.bufferSize > 0 {
+			writer = bufio.NewWriterSize(w, r.bufferSize)
+		} else {
+			writer = bufio.NewWriter(w)
+		}
 	}
+	root := n
+	count := 0
+	truncated := false
+	var skipped ast.Node
 	err := ast.Walk(n, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if r.maxNodes > 0 && n != root {
+			if entering {
+				count++
+				if count > r.maxNodes {
+					truncated = true
+					skipped = n
+					return ast.WalkSkipChildren, nil
+				}
+			} else if n == skipped {
+				// skipped nodes are left immediately because their children are skipped.
+				return ast.WalkContinue, nil
+			}
+		}
 		s := ast.WalkStatus(ast.WalkContinue)
 		var err error
 		f := r.nodeRendererFuncs[n.Kind()]
@@ -170,5 +242,8 @@ func (r *renderer) Render(w io.Writer, source []byte, n ast.Node) error {
 	if err != nil {
 		return err
 	}
+	if truncated {
+		_, _ = writer.Write(r.truncationNotice)
+	}
 	return writer.Flush()
 }
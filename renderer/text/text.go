@@ -0,0 +1,182 @@
+// Package text implements renderer that outputs plain texts.
+package text
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+// A Config struct has configurations for the plain text based renderers.
+type Config struct {
+	// SoftLineBreak indicates that soft line breaks should be rendered as
+	// newlines. Otherwise, soft line breaks are rendered as spaces.
+	SoftLineBreak bool
+}
+
+// NewConfig returns a new Config with defaults.
+func NewConfig() Config {
+	return Config{
+		SoftLineBreak: false,
+	}
+}
+
+// SetOption implements renderer.SetOptioner.
+func (c *Config) SetOption(name renderer.OptionName, value interface{}) {
+	switch name {
+	case optSoftLineBreak:
+		c.SoftLineBreak = value.(bool)
+	}
+}
+
+// An Option interface sets options for plain text based renderers.
+type Option interface {
+	SetTextOption(*Config)
+}
+
+// SoftLineBreak is an option name used in WithSoftLineBreak.
+const optSoftLineBreak renderer.OptionName = "TextSoftLineBreak"
+
+type withSoftLineBreak struct {
+	value bool
+}
+
+func (o *withSoftLineBreak) SetConfig(c *renderer.Config) {
+	c.Options[optSoftLineBreak] = o.value
+}
+
+func (o *withSoftLineBreak) SetTextOption(c *Config) {
+	c.SoftLineBreak = o.value
+}
+
+// WithSoftLineBreak is a functional option that indicates whether soft line
+// breaks should be rendered as newlines(true) or spaces(false).
+func WithSoftLineBreak(value bool) interface {
+	renderer.Option
+	Option
+} {
+	return &withSoftLineBreak{value}
+}
+
+// A Renderer struct is an implementation of renderer.Renderer that renders
+// only texts in nodes.
+// Links are rendered as their labels and images are rendered as their
+// alt texts. Raw HTML is not rendered.
+type Renderer struct {
+	Config
+}
+
+// NewRenderer returns a new Renderer with given options.
+func NewRenderer(opts ...Option) renderer.Renderer {
+	r := &Renderer{
+		Config: NewConfig(),
+	}
+	for _, opt := range opts {
+		opt.SetTextOption(&r.Config)
+	}
+	return r
+}
+
+// AddOptions implements renderer.Renderer.AddOptions.
+func (r *Renderer) AddOptions(opts ...renderer.Option) {
+	config := renderer.NewConfig()
+	for _, opt := range opts {
+		opt.SetConfig(config)
+	}
+	for name, value := range config.Options {
+		r.SetOption(name, value)
+	}
+}
+
+// isTextBlock returns true if the given node is a block that has texts
+// directly.
+func isTextBlock(n ast.Node) bool {
+	if n.Type() != ast.TypeBlock || n.Kind() == ast.KindHTMLBlock {
+		return false
+	}
+	if n.IsRaw() {
+		return true
+	}
+	fc := n.FirstChild()
+	return fc != nil && fc.Type() == ast.TypeInline
+}
+
+// Render implements renderer.Renderer.Render.
+func (r *Renderer) Render(w io.Writer, source []byte, n ast.Node) error {
+	writer, ok := w.(util.BufWriter)
+	if !ok {
+		writer = bufio.NewWriter(w)
+	}
+	hasBlock := false
+	err := ast.Walk(n, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if isTextBlock(n) {
+			if !entering {
+				hasBlock = true
+			} else if hasBlock {
+				// blocks are separated by a blank line.
+				_, _ = writer.WriteString("\n\n")
+			}
+		}
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.CodeBlock, *ast.FencedCodeBlock:
+			// lines are written as they are without copying, trailing
+			// newlines are written only if they are followed by contents.
+			newlines := 0
+			l := n.Lines().Len()
+			for i := 0; i < l; i++ {
+				line := n.Lines().At(i)
+				value := line.Value(source)
+				trimmed := bytes.TrimRight(value, "\n")
+				if len(trimmed) != 0 {
+					for ; newlines > 0; newlines-- {
+						_ = writer.WriteByte('\n')
+					}
+					_, _ = writer.Write(trimmed)
+				}
+				newlines += len(value) - len(trimmed)
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			value := n.Segment.Value(source)
+			if !n.IsRaw() {
+				value = resolveReferences(util.UnescapePunctuations(value))
+			}
+			_, _ = writer.Write(value)
+			if n.HardLineBreak() || (n.SoftLineBreak() && r.SoftLineBreak) {
+				_ = writer.WriteByte('\n')
+			} else if n.SoftLineBreak() {
+				_ = writer.WriteByte(' ')
+			}
+		case *ast.String:
+			// strings like typographer's ones may have character references.
+			_, _ = writer.Write(resolveReferences(n.Value))
+		case *ast.CodeSpan:
+			for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+				if t, ok := c.(*ast.Text); ok {
+					_, _ = writer.Write(bytes.ReplaceAll(t.Segment.Value(source), []byte("\n"), []byte(" ")))
+				}
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.AutoLink:
+			_, _ = writer.Write(n.Label(source))
+		case *ast.RawHTML, *ast.HTMLBlock:
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	if err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+func resolveReferences(value []byte) []byte {
+	return util.ResolveEntityNames(util.ResolveNumericReferences(value))
+}
@@ -0,0 +1,84 @@
+package text_test
+
+import (
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	. "github.com/yuin/goldmark/renderer/text"
+	"github.com/yuin/goldmark/testutil"
+)
+
+func TestRenderer(t *testing.T) {
+	source := `# Title &amp; *emphasis*
+
+A [link](/url) and ![alt *text*](/image.png) with ` + "`code`" + `,
+soft \* break
+hard break, <b>raw</b> HTML and <http://example.com>.
+
+- item 1
+- item 2
+
+> quote
+
+` + "```" + `
+code block
+` + "```" + `
+
+<div>
+html block
+</div>
+
+[ref]: /ref
+
+last paragraph
+`
+	testutil.DoTestCase(
+		goldmark.New(goldmark.WithRenderer(NewRenderer())),
+		testutil.MarkdownTestCase{
+			No:          1,
+			Description: "Soft line breaks are rendered as spaces",
+			Markdown:    source,
+			Expected: `Title & emphasis
+
+A link and alt text with code, soft * break hard break, raw HTML and http://example.com.
+
+item 1
+
+item 2
+
+quote
+
+code block
+
+last paragraph`,
+		},
+		t,
+	)
+	testutil.DoTestCase(
+		goldmark.New(
+			goldmark.WithRenderer(NewRenderer()),
+			goldmark.WithRendererOptions(WithSoftLineBreak(true)),
+		),
+		testutil.MarkdownTestCase{
+			No:          2,
+			Description: "Soft line breaks are rendered as newlines",
+			Markdown:    "foo\nbar  \nbaz",
+			Expected:    "foo\nbar\nbaz",
+		},
+		t,
+	)
+	testutil.DoTestCase(
+		goldmark.New(
+			goldmark.WithRenderer(NewRenderer()),
+			goldmark.WithExtensions(extension.Typographer),
+		),
+		testutil.MarkdownTestCase{
+			No:          3,
+			Description: "Typographer strings are rendered as characters",
+			Markdown:    `"quoted" -- text...`,
+			Expected:    "“quoted” – text…",
+		},
+		t,
+	)
+}